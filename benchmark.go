@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// benchmarkConfig plays benchmarkGames games of the resolved configuration without any output, instead of a game,
+// and reports how fast they ran, to estimate the time of larger runs. The games cycle through the lucky colors and
+// the packages, drawing from the generator of -seed. The multiplier cards of -multiplier-cards are not drawn.
+var benchmarkConfig = flag.Bool("benchmark-config", false, "report the throughput of the configuration over headless games, then exit")
+
+// benchmarkGames is the number of games played by -benchmark-config.
+const benchmarkGames = 300
+
+// runBenchmark function plays the games of -benchmark-config and prints their throughput to w.
+func runBenchmark(w io.Writer) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for i := range benchmarkGames {
+		if *finiteStock {
+			if err := initStock(); err != nil {
+				die("-stock is invalid, %v", err)
+			}
+		}
+		lastDrawn = 0
+		packageSize := packages[i%len(packages)]
+		applyPackageRewards(packageSize)
+		g := newGame(i%*numColors+1, packageSize)
+		g.boostStarter(starterSlots(g.board))
+		g.silent = true
+		for !g.over() {
+			g.playTurn(discardRenderer{})
+		}
+		g.finish()
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	_, _ = fmt.Fprintf(w, "Benchmark: %d games in %s, %.0f games/s, %d allocs/game\n", benchmarkGames,
+		elapsed.Round(time.Millisecond), benchmarkGames/elapsed.Seconds(), (after.Mallocs-before.Mallocs)/benchmarkGames)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRunBenchmark(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, 1)
+	var buf bytes.Buffer
+	runBenchmark(&buf)
+	out := buf.String()
+	_, rest, ok := strings.Cut(out, ", ")
+	if !ok {
+		t.Fatalf("unexpected report %q", out)
+	}
+	var rate float64
+	if _, err := fmt.Sscanf(rest, "%f games/s", &rate); err != nil {
+		t.Fatalf("parse the games/s of %q: %v", out, err)
+	}
+	if rate <= 0 {
+		t.Errorf("reported %v games/s, want a positive throughput", rate)
+	}
+}
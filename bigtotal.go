@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// bigTotal prints the number of toys received at the end of the game in large ASCII-art digits, e.g. for a kiosk
+// revealing the score. The digits are skipped in the JSON output and under -quiet.
+var bigTotal = flag.Bool("big-total", false, "print the total of toys received in large ASCII-art digits")
+
+// bigDigits holds the rows of the ASCII-art figure of each digit, indexed by digit.
+var bigDigits = [10][5]string{
+	{" ### ", "#   #", "#   #", "#   #", " ### "},
+	{"  #  ", " ##  ", "  #  ", "  #  ", " ### "},
+	{" ### ", "#   #", "  ## ", " #   ", "#####"},
+	{"#### ", "    #", " ### ", "    #", "#### "},
+	{"#  # ", "#  # ", "#####", "   # ", "   # "},
+	{"#####", "#    ", "#### ", "    #", "#### "},
+	{" ### ", "#    ", "#### ", "#   #", " ### "},
+	{"#####", "   # ", "  #  ", " #   ", " #   "},
+	{" ### ", "#   #", " ### ", "#   #", " ### "},
+	{" ### ", "#   #", " ####", "    #", " ### "},
+}
+
+// printBigNumber function prints n, which is not negative, in large ASCII-art digits, the figures of its digits separated by a column of spaces.
+func printBigNumber(w io.Writer, n int) {
+	digits := strconv.Itoa(n)
+	for row := range len(bigDigits[0]) {
+		cells := make([]string, 0, len(digits))
+		for _, d := range digits {
+			cells = append(cells, bigDigits[d-'0'][row])
+		}
+		_, _ = fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPrintBigNumber(t *testing.T) {
+	var buf bytes.Buffer
+	printBigNumber(&buf, 407)
+	rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(rows) != len(bigDigits[0]) {
+		t.Fatalf("the figure holds %d rows, want %d:\n%s", len(rows), len(bigDigits[0]), buf.String())
+	}
+	for r, row := range rows {
+		// Three cells of five columns, separated by a column of spaces.
+		row = fmt.Sprintf("%-17s", row)
+		for k, d := range []int{4, 0, 7} {
+			if cell := row[6*k : 6*k+5]; cell != bigDigits[d][r] {
+				t.Errorf("row %d, cell %d = %q, want the digit %d, %q", r, k, cell, d, bigDigits[d][r])
+			}
+		}
+	}
+}
+
+func TestTextRendererSummaryBigTotal(t *testing.T) {
+	setFlag(t, "big-total", "true")
+	for _, quiet := range []string{"false", "true"} {
+		setFlag(t, "quiet", quiet)
+		var buf bytes.Buffer
+		newTextRenderer(&buf, false).Summary(gameResult{Acquired: colorTally(make([]int, *numColors)), Total: 407})
+		if shown := strings.Contains(buf.String(), bigDigits[4][4]); shown != (quiet == "false") {
+			t.Errorf("-quiet=%s: big total shown %v, want %v:\n%s", quiet, shown, quiet == "false", buf.String())
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// boardFile starts the game on a hand-crafted board, read from a grid template with one row of the board per line
+// and space-separated cells, each either "." for an empty slot or the abbreviation of a color:
+//
+//	# Blank lines and lines starting with # are ignored.
+//	R  R  .
+//	Pu Pi .
+//	Bl Br R
+//
+// The abbreviation of a color is the shortest prefix of its name that no other color in play shares, see
+// colorAbbreviations; full names are accepted as well, and case is ignored. The first turn places toys in the empty
+// slots of the template, then resolves the board as usual.
+var boardFile = flag.String("board-file", "", "start the game on the board of this grid template")
+
+// initialBoard holds the board of -board-file, or nil to start on an empty board.
+var initialBoard []int
+
+// colorAbbreviations function returns the abbreviation of each color in play, indexed by color (0-based): the
+// shortest prefix of its name that is not the prefix of another color in play, e.g. "R" for Red but "Pu" for Purple
+// and "Pi" for Pink.
+func colorAbbreviations() []string {
+	abbrevs := make([]string, *numColors)
+	for k, c := range colors[:*numColors] {
+		for n := 1; n <= len(c); n++ {
+			unique := true
+			for j, other := range colors[:*numColors] {
+				if j != k && strings.HasPrefix(strings.ToLower(other), strings.ToLower(c[:n])) {
+					unique = false
+					break
+				}
+			}
+			if unique || n == len(c) {
+				abbrevs[k] = c[:n]
+				break
+			}
+		}
+	}
+	return abbrevs
+}
+
+// loadBoardFile function reads the grid template of the file at path into initialBoard.
+func loadBoardFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	abbrevs := colorAbbreviations()
+	board := make([]int, 0, boardSize*boardSize)
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cells := strings.Fields(text)
+		if len(cells) != boardSize {
+			return fmt.Errorf("%s:%d: want %d cells, got %d", path, line, boardSize, len(cells))
+		}
+		if len(board) == boardSize*boardSize {
+			return fmt.Errorf("%s:%d: want %d rows, got more", path, line, boardSize)
+		}
+		for _, cell := range cells {
+			v, err := parseBoardCell(cell, abbrevs)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			board = append(board, v)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if len(board) != boardSize*boardSize {
+		return fmt.Errorf("%s: want %d rows, got %d", path, boardSize, len(board)/boardSize)
+	}
+	initialBoard = board
+	return nil
+}
+
+// parseBoardCell function parses a cell of a grid template: 0 for ".", or the color (1-based) of an abbreviation of
+// abbrevs or of a full color name.
+func parseBoardCell(cell string, abbrevs []string) (int, error) {
+	if cell == "." {
+		return 0, nil
+	}
+	for k, a := range abbrevs {
+		if strings.EqualFold(cell, a) || strings.EqualFold(cell, colors[k]) {
+			return k + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown cell %q, want . or one of %s", cell, strings.Join(abbrevs, " "))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadBoardFileTriple(t *testing.T) {
+	saveEvents(t)
+	old := initialBoard
+	t.Cleanup(func() { initialBoard = old })
+	path := filepath.Join(t.TempDir(), "board.txt")
+	template := "# Red across the top row\nR r Red\nPu Pi .\nbl Br .\n"
+	if err := os.WriteFile(path, []byte(template), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadBoardFile(path); err != nil {
+		t.Fatalf("load the board: %v", err)
+	}
+	if want := []int{1, 1, 1, 3, 7, 0, 8, 9, 0}; !slices.Equal(initialBoard, want) {
+		t.Fatalf("board = %v, want %v", initialBoard, want)
+	}
+	board := slices.Clone(initialBoard)
+	events, _, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if !slices.Contains(eventTypes(events), eventLuckyStrike) {
+		t.Errorf("events = %v, want the top row to fire %s", eventTypes(events), eventDesc[eventLuckyStrike])
+	}
+}
+
+func TestLoadBoardFileInvalid(t *testing.T) {
+	old := initialBoard
+	t.Cleanup(func() { initialBoard = old })
+	for _, template := range []string{"R R R\nR R\nR R R\n", "R R R\nR R R\n", "R R R\nR R R\nR R X\n"} {
+		path := filepath.Join(t.TempDir(), "board.txt")
+		if err := os.WriteFile(path, []byte(template), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := loadBoardFile(path); err == nil {
+			t.Errorf("the template %q was accepted", template)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "flag"
+
+// bonusBoard adds a secondary board fed by the matches of the main board: every pair, line and custom shape cleared
+// from the main board places a toy of its color on the bonus board, whose lines and pairs are then resolved, see
+// checkBonusBoard. The events of the bonus board are reported with the events of the turn and award their points, but
+// the toys of the bonus board are only tokens of toys already acquired: its events acquire no toy. A token arriving
+// when the bonus board is full overflows and is discarded.
+var bonusBoard = flag.Bool("bonus-board", false, "feed a secondary board with the matches of the main board, for extra rewards")
+
+// resolveBonusBoard method places a token on the bonus board for each pair, line and shape of events, resolves the
+// bonus board and returns events followed by the events of the bonus board.
+func (g *game) resolveBonusBoard(events []ev) []ev {
+	for _, e := range events {
+		if eventAcquired[e.event] == 0 {
+			continue
+		}
+		for c := range e.acquired {
+			if len(g.bonusSlots) == 0 {
+				continue
+			}
+			g.bonus[g.bonusSlots[0]] = c
+			g.bonusSlots = g.bonusSlots[1:]
+		}
+	}
+	bonusEvents := checkBonusBoard(g.bonus)
+	g.bonusSlots = emptySlots(g.bonus)
+	return append(events, bonusEvents...)
+}
+
+// checkBonusBoard function clears the completed lines of the bonus board, then pairs the toys left in slot order like
+// checkBoard, and returns a Lucky Strike or a One Pair event, acquiring no toy, for each of them. Only the built-in
+// lines and the pairs are detected on the bonus board: neither the custom events and shapes nor Family Portrait and
+// Clear The Board fire there, and -max-events-per-turn, -pair-greedy, -sticky and -gravity do not apply to it.
+func checkBonusBoard(board []int) []ev {
+	events := make([]ev, 0)
+	completed := make([][]int, 0)
+	for _, line := range tripleCombination {
+		if filled(board, line) {
+			completed = append(completed, line)
+			events = append(events, ev{map[int]int{}, eventLuckyStrike})
+		}
+	}
+	for _, line := range completed {
+		for _, k := range line {
+			board[k] = 0
+		}
+	}
+	first := make(map[int]int)
+	for k, v := range board {
+		if v == 0 {
+			continue
+		}
+		if pos, ok := first[v]; ok {
+			events = append(events, ev{map[int]int{}, eventOnePair})
+			board[pos], board[k] = 0, 0
+			delete(first, v)
+		} else {
+			first[v] = k
+		}
+	}
+	return events
+}
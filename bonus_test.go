@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBonusBoardLine(t *testing.T) {
+	setFlag(t, "bonus-board", "true")
+	g := newGame(9, 30)
+	g.board[0], g.bonus[0], g.bonus[1] = 1, 1, 1
+	g.bonusSlots = emptySlots(g.bonus)
+	g.orderedEmptySlots, g.remaining, g.draw = emptySlots(g.board), 1, drawSequence(1)
+	// The Red pair empties the main board and places a Red token on the bonus board, completing its top row.
+	events := g.playTurn(discardRenderer{})
+	if want := []int{eventOnePair, eventClear, eventLuckyStrike}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want %v", eventTypes(events), want)
+	}
+	mainBoard := eventRewardRules[eventOnePair] + eventRewardRules[eventClear]
+	if want := mainBoard + eventRewardRules[eventLuckyStrike]; g.score != want {
+		t.Errorf("score = %d, want %d with the bonus Lucky Strike", g.score, want)
+	}
+	if g.acquired[0] != 2 {
+		t.Errorf("acquired %d Red, want the 2 of the pair only", g.acquired[0])
+	}
+	if !slices.Equal(g.bonus, make([]int, 9)) {
+		t.Errorf("bonus board = %v, want its line cleared", g.bonus)
+	}
+}
+
+func TestCheckBonusBoardLinesAndPairsOnly(t *testing.T) {
+	saveEvents(t)
+	old := stickySlots
+	stickySlots = map[int]bool{0: true}
+	t.Cleanup(func() { stickySlots = old })
+	customEvents = []customEvent{{eventOnePair, func([]int) (bool, int) { return true, 0 }}}
+
+	full := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if events := checkBonusBoard(full); len(events) != 0 {
+		t.Errorf("events of a full bonus board of distinct colors = %v, want none", eventTypes(events))
+	}
+	board := []int{2, 2, 2, 3, 0, 0, 3, 0, 0}
+	events := checkBonusBoard(board)
+	if want := []int{eventLuckyStrike, eventOnePair}; !slices.Equal(eventTypes(events), want) {
+		t.Errorf("events = %v, want %v", eventTypes(events), want)
+	}
+	if !slices.Equal(board, make([]int, 9)) {
+		t.Errorf("bonus board = %v, want the line and the pair cleared, the sticky slot 0 included", board)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// branches explores alternate outcomes of the game: right after the turn branchTurn, the draw state is checkpointed
+// and the game is played to its end branches times, silently and each time with a different stream of draws.
+// The spread of the outcomes is printed, then the checkpoint is restored so that the game goes on exactly as it would
+// have without the analysis.
+var (
+	branches   = flag.Int("branch", 0, "play this many alternate continuations after -branch-turn and print their outcomes")
+	branchTurn = flag.Int("branch-turn", 1, "turn after which the continuations of -branch start")
+)
+
+// discardRenderer is a Renderer that renders nothing, for the games played by the analyses.
+type discardRenderer struct{}
+
+func (discardRenderer) Board(board, placed []int)                {}
+func (discardRenderer) Events(events []ev)                       {}
+func (discardRenderer) Acquired(acq []int, remaining, score int) {}
+func (discardRenderer) Summary(res gameResult)                   {}
+
+// clone method returns a copy of the game, played silently: the copy sends no webhook notifications and does not
+// pause on resets.
+func (g *game) clone() *game {
+	c := *g
+	c.board = slices.Clone(g.board)
+	c.acquired = slices.Clone(g.acquired)
+	c.orderedEmptySlots = slices.Clone(g.orderedEmptySlots)
+	c.bonus = slices.Clone(g.bonus)
+	c.bonusSlots = slices.Clone(g.bonusSlots)
+	c.stats.turns = slices.Clone(g.stats.turns)
+	c.confirmReset = nil
+	c.silent = true
+	return &c
+}
+
+// branchOutcomes method plays n continuations of the game from its current state and returns their outcomes.
+// The i-th continuation draws from the stream i+1 of the seed of the game. The draw state is restored afterwards.
+func (g *game) branchOutcomes(n int) []gameResult {
+	checkpoint := checkpointRNG()
+	outcomes := make([]gameResult, n)
+	for i := range outcomes {
+		rngSource.src.Seed(*seed, uint64(i+1))
+		b := g.clone()
+		for !b.over() {
+			b.playTurn(discardRenderer{})
+		}
+		outcomes[i] = b.finish()
+	}
+	restoreRNG(checkpoint)
+	return outcomes
+}
+
+// printBranches function prints the spread of the total toys and the score of outcomes to w.
+func printBranches(w io.Writer, turn int, outcomes []gameResult) {
+	toys, scores := make([]int, len(outcomes)), make([]int, len(outcomes))
+	for i, res := range outcomes {
+		toys[i], scores[i] = res.Total, res.Score
+	}
+	_, _ = fmt.Fprintf(w, "========== branches ==========\n")
+	_, _ = fmt.Fprintf(w, "%d continuations after turn %d\n", len(outcomes), turn)
+	_, _ = fmt.Fprintf(w, "Toys: %s\n", spread(toys))
+	_, _ = fmt.Fprintf(w, "Score: %s\n", spread(scores))
+}
+
+// spread function describes the minimum, the mean and the maximum of values.
+func spread(values []int) string {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return fmt.Sprintf("min %d, mean %.1f, max %d", slices.Min(values), float64(sum)/float64(len(values)), slices.Max(values))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// multiplierCards is a deck of multiplier cards, one of which is drawn with the seed of the game at the start of the
+// game and multiplies the rewards for the whole game. The cards are comma-separated, each either "N" to multiply the
+// points of every event by N, or "N:event" to multiply the points of that event only, e.g. "1,2:one-pair,2:lucky-strike".
+var multiplierCards = flag.String("multiplier-cards", "", "deck of multiplier cards, one drawn per game: N or N:event, comma-separated")
+
+// multiplierDeck holds the cards of -multiplier-cards, parsed once the events are configured.
+var multiplierDeck []multiplierCard
+
+// multiplierCard is a card of the deck of -multiplier-cards.
+type multiplierCard struct {
+	factor int
+	// event is the event type whose points are multiplied, or -1 for every event.
+	event int
+}
+
+// String method returns the description of the card, e.g. "x2 on One Pair".
+func (c multiplierCard) String() string {
+	if c.event < 0 {
+		return fmt.Sprintf("x%d on every event", c.factor)
+	}
+	return fmt.Sprintf("x%d on %s", c.factor, eventDesc[c.event])
+}
+
+// parseMultiplierCards function parses the deck of -multiplier-cards.
+func parseMultiplierCards(spec string) ([]multiplierCard, error) {
+	deck := make([]multiplierCard, 0)
+	for _, field := range strings.Split(spec, ",") {
+		factor, name, scoped := strings.Cut(strings.TrimSpace(field), ":")
+		n, err := strconv.Atoi(factor)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid factor in card %q", field)
+		}
+		card := multiplierCard{n, -1}
+		if scoped {
+			if card.event = slices.Index(eventNames, name); card.event < 0 {
+				return nil, fmt.Errorf("unknown event in card %q", field)
+			}
+		}
+		deck = append(deck, card)
+	}
+	return deck, nil
+}
+
+// applyMultiplierCard function draws a card of -multiplier-cards, announces it and multiplies the rewards accordingly.
+// Like applyPackageRewards, it runs before the first turn, so that handleEvents and the renderers all agree on the
+// rewards.
+func applyMultiplierCard() {
+	if len(multiplierDeck) == 0 {
+		return
+	}
+	card := multiplierDeck[rng.IntN(len(multiplierDeck))]
+	_, _ = fmt.Fprintf(ui, "Multiplier card: %s\n", card)
+	for e := range eventRewardRules {
+		if card.event < 0 || card.event == e {
+			eventRewardRules[e] *= card.factor
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestMultiplierCardStrikes(t *testing.T) {
+	saveEvents(t)
+	oldDeck := multiplierDeck
+	t.Cleanup(func() { multiplierDeck = oldDeck })
+	// Points add no toys under -strict-count, so that both games place the same toys whatever the rewards.
+	setFlag(t, "strict-count", "true")
+	captureUI(t)
+	rewards, strike := maps.Clone(eventRewardRules), eventRewardRules[eventLuckyStrike]
+	play := func(deck string) (gameResult, []turnRecord) {
+		eventRewardRules = maps.Clone(rewards)
+		var err error
+		if multiplierDeck, err = parseMultiplierCards(deck); err != nil {
+			t.Fatalf("parse the deck %q: %v", deck, err)
+		}
+		useSeed(t, demoSeed)
+		// Both decks hold a single card, whose draw takes the same call to the generator.
+		applyMultiplierCard()
+		g := newGame(1, 30)
+		return playGame(g, discardRenderer{}), g.stats.turns
+	}
+	plain, turns := play("1")
+	doubled, _ := play("2:lucky-strike")
+	strikes := countEvents(turns, eventDesc[eventLuckyStrike])
+	if strikes == 0 {
+		t.Fatal("the game fired no Lucky Strike")
+	}
+	if want := plain.Score + strikes*strike; doubled.Score != want {
+		t.Errorf("score = %d with x2 on Lucky Strike, want %d for %d strikes of %d points on top of %d",
+			doubled.Score, want, strikes, strike, plain.Score)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"slices"
+)
+
+// luckyCenter places a drawn toy of the lucky color in the center slot of the board while it is empty, instead of the
+// next slot in order. When the turn then completes a line of the lucky color through the center, the Lucky Center
+// event awards a bonus on top of the Lucky Strike.
+// See placeInSlot.
+var luckyCenter = flag.Bool("lucky-center", false, "place a drawn lucky color in the empty center slot, with a bonus if it completes a line")
+
+// centerSlot is the slot in the middle of the board.
+const centerSlot = boardSize * boardSize / 2
+
+// luckyCenterPoints is the bonus awarded by the Lucky Center event.
+const luckyCenterPoints = 2
+
+// eventLuckyCenter is the event type of Lucky Center, registered by registerLuckyCenter under -lucky-center.
+var eventLuckyCenter = -1
+
+// registerLuckyCenter function registers the Lucky Center event next to the built-in events.
+func registerLuckyCenter() {
+	eventLuckyCenter = registerEvent("Lucky Center",
+		"the lucky color completed a line through the center slot, %d more toys to place", luckyCenterPoints)
+}
+
+// takeCenterSlot function moves the center slot to the front of orderedEmptySlots, so that it is the next slot filled,
+// and reports whether it was empty. orderedEmptySlots is left untouched; the returned slots are a copy.
+func takeCenterSlot(orderedEmptySlots []int) ([]int, bool) {
+	i := slices.Index(orderedEmptySlots, centerSlot)
+	if i < 0 {
+		return orderedEmptySlots, false
+	}
+	slots := slices.Delete(slices.Clone(orderedEmptySlots), i, i+1)
+	return slices.Insert(slots, 0, centerSlot), true
+}
+
+// centerLineComplete function reports whether a line through the center slot holds three toys of its color.
+func centerLineComplete(board []int) bool {
+	for _, comb := range tripleCombination {
+		if !slices.Contains(comb, centerSlot) {
+			continue
+		}
+		if board[comb[0]] == board[centerSlot] && board[comb[1]] == board[centerSlot] && board[comb[2]] == board[centerSlot] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// drawSequence function returns a draw function that returns the colors of seq in turn.
+func drawSequence(seq ...int) func() int {
+	return func() int {
+		c := seq[0]
+		seq = seq[1:]
+		return c
+	}
+}
+
+func TestPlaceInSlotLuckyCenter(t *testing.T) {
+	saveEvents(t)
+	setFlag(t, "lucky-center", "true")
+	registerLuckyCenter()
+	board := make([]int, 9)
+	_, events, _ := placeInSlot(board, emptySlots(board), make([]ev, 0), 2, 1, drawSequence(3, 1), nil)
+	if want := []int{3, 0, 0, 0, 1, 0, 0, 0, 0}; !slices.Equal(board, want) {
+		t.Errorf("board = %v, want the lucky color in the center, %v", board, want)
+	}
+	if want := []int{eventLuckyColor}; !slices.Equal(eventTypes(events), want) {
+		t.Errorf("events = %v, want %v", eventTypes(events), want)
+	}
+
+	// The lucky color placed in the center completes the middle row.
+	board = []int{2, 3, 0, 1, 0, 1, 0, 0, 0}
+	_, events, _ = placeInSlot(board, emptySlots(board), make([]ev, 0), 1, 1, drawSequence(1), nil)
+	if want := []int{eventLuckyColor, eventLuckyCenter}; !slices.Equal(eventTypes(events), want) {
+		t.Errorf("events = %v, want %v", eventTypes(events), want)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// eventPointsChanceSpec models an imperfect machine that sometimes fails to pay: each event listed, as comma-separated
+// name=chance pairs such as "one-pair=0.8,lucky-strike=0.5", only awards its points with that chance when it fires.
+// The names are those of the rewards of -dump-config. The chance only applies to the points: an event that misses
+// them still resolves the board as usual and its toys are still acquired, but it is not reported. Unlisted events
+// always award their points. See applyEventPointsChances.
+var eventPointsChanceSpec = flag.String("event-points-chance", "", "chance that each listed event awards its points when it fires, as name=chance pairs")
+
+// eventPointsChances holds the chance of the events of -event-points-chance, by event type.
+var eventPointsChances = map[int]float64{}
+
+// parseEventPointsChances function parses the chances of -event-points-chance into eventPointsChances.
+func parseEventPointsChances(spec string) error {
+	for _, field := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		p, err := strconv.ParseFloat(value, 64)
+		if !ok || err != nil || p < 0 || p > 1 {
+			return fmt.Errorf("invalid chance %q, want name=chance with a chance from 0 to 1", field)
+		}
+		e := slices.Index(eventNames, name)
+		if e < 0 {
+			return fmt.Errorf("unknown event %q", name)
+		}
+		eventPointsChances[e] = p
+	}
+	return nil
+}
+
+// applyEventPointsChances function drops the events that miss their points by the chances of -event-points-chance,
+// acquiring their toys in acq, indexed by color (0-based), and returns the events that award them. The generator is
+// only used for the events whose chance is below 1, so that the games of a seed are unchanged without them.
+func applyEventPointsChances(events []ev, acq []int) []ev {
+	return slices.DeleteFunc(events, func(e ev) bool {
+		p, ok := eventPointsChances[e.event]
+		if !ok || p >= 1 || rng.Float64() < p {
+			return false
+		}
+		for k, v := range e.acquired {
+			acq[k-1] += v
+		}
+		return true
+	})
+}
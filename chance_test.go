@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEventPointsChanceZeroPairs(t *testing.T) {
+	saveEvents(t)
+	// Up to its first pair, the game plays the same with or without the chance: only pairs draw from the generator.
+	useSeed(t, 1)
+	if !slices.Contains(playedEvents(newGame(1, 30)), eventOnePair) {
+		t.Fatal("the game of seed 1 forms no pair")
+	}
+	if err := parseEventPointsChances("one-pair=0"); err != nil {
+		t.Fatal(err)
+	}
+	useSeed(t, 1)
+	if slices.Contains(playedEvents(newGame(1, 30)), eventOnePair) {
+		t.Error("a pair awarded its points at chance 0")
+	}
+
+	acq := make([]int, *numColors)
+	events := []ev{{map[int]int{2: 2}, eventOnePair}, {map[int]int{1: 3}, eventLuckyStrike}}
+	events = applyEventPointsChances(events, acq)
+	if want := []int{eventLuckyStrike}; !slices.Equal(eventTypes(events), want) {
+		t.Errorf("events = %v, want the pair dropped, %v", eventTypes(events), want)
+	}
+	if acq[1] != 2 {
+		t.Errorf("acquired %v, want the 2 Yellow toys of the dropped pair acquired", acq)
+	}
+}
+
+func TestParseEventPointsChancesInvalid(t *testing.T) {
+	saveEvents(t)
+	for _, spec := range []string{"one-pair", "one-pair=1.5", "one-pair=-0.1", "one-pair=x", "no-such-event=0.5"} {
+		if err := parseEventPointsChances(spec); err == nil {
+			t.Errorf("-event-points-chance %q was accepted", spec)
+		}
+	}
+}
+
+// playedEvents function plays g to its end and returns the types of the events reported over its turns.
+func playedEvents(g *game) []int {
+	types := make([]int, 0)
+	for !g.over() {
+		types = append(types, eventTypes(g.playTurn(discardRenderer{}))...)
+	}
+	return types
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// collectionIn and collectionOut are JSON files holding a color-name keyed tally of toys collected over several games.
+// The exported tally is the imported one plus the toys acquired in this game, so pointing both flags at the same file
+// keeps a running collection across sessions.
+var (
+	collectionIn  = flag.String("collection-in", "", "load a starting collection tally from this JSON file")
+	collectionOut = flag.String("collection-out", "", "write the collection tally, including this game, to this JSON file")
+)
+
+// loadCollection function reads a color-name keyed tally from path and returns it indexed by color (0-based).
+// Unknown color names and negative counts are rejected.
+func loadCollection(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tally := make(map[string]int)
+	if err := json.Unmarshal(data, &tally); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	coll := make([]int, len(colors))
+	for name, n := range tally {
+		idx := slices.Index(colors, name)
+		if idx < 0 {
+			return nil, fmt.Errorf("parse %s: unknown color %q", path, name)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("parse %s: negative count %d for %s", path, n, name)
+		}
+		coll[idx] = n
+	}
+	return coll, nil
+}
+
+// saveCollection function writes coll, indexed by color (0-based), to path as a color-name keyed JSON tally.
+func saveCollection(path string, coll []int) error {
+	data, err := json.MarshalIndent(colorTally(coll), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// mergeCollection function returns a new tally holding coll plus the toys in acq.
+func mergeCollection(coll, acq []int) []int {
+	merged := slices.Clone(coll)
+	for k, v := range acq {
+		merged[k] += v
+	}
+	return merged
+}
+
+// luckyColorSpec picks the lucky color of an interactive game instead of the prompt: either the name of a color in
+// play, or fill-gap for the color that recommendLuckyColor recommends for the collection of -collection-in.
+var luckyColorSpec = flag.String("color", "", "lucky color, by name or fill-gap for the color the collection is shortest on, instead of the prompt")
+
+// pickLuckyColor function returns the lucky color (1-based) of -color for the collection coll, and announces it.
+func pickLuckyColor(coll []int) int {
+	if *luckyColorSpec == "fill-gap" {
+		c := recommendLuckyColor(coll)
+		_, _ = fmt.Fprintf(ui, "Fill gap: your collection is shortest on %s, it is your lucky color\n", colors[c-1])
+		return c
+	}
+	c := slices.IndexFunc(colors[:*numColors], func(name string) bool { return strings.EqualFold(name, *luckyColorSpec) })
+	_, _ = fmt.Fprintf(ui, "You choose %s \n", colors[c])
+	return c + 1
+}
+
+// validLuckyColorSpec function reports whether -color is fill-gap, with a collection to fill, or names a color in play.
+func validLuckyColorSpec() bool {
+	if *luckyColorSpec == "fill-gap" {
+		return *collectionIn != ""
+	}
+	return slices.ContainsFunc(colors[:*numColors], func(name string) bool { return strings.EqualFold(name, *luckyColorSpec) })
+}
+
+// recommendLuckyColor function returns the lucky color (1-based) that best fills the gaps of coll: the color in play
+// with the fewest toys in the collection among the colors that can be drawn, ties broken by the higher draw odds,
+// then by palette order.
+func recommendLuckyColor(coll []int) int {
+	weights := colorWeights(0)
+	best := -1
+	for k, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if best < 0 || coll[k] < coll[best] || (coll[k] == coll[best] && w > weights[best]) {
+			best = k
+		}
+	}
+	return best + 1
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCollectionRoundTrip(t *testing.T) {
+	useSeed(t, 1)
+	path := filepath.Join(t.TempDir(), "collection.json")
+	if err := os.WriteFile(path, []byte(`{"Red": 4, "Blue": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	coll, err := loadCollection(path)
+	if err != nil {
+		t.Fatalf("load the collection: %v", err)
+	}
+	g := newGame(1, 9)
+	res := playGame(g, discardRenderer{})
+	if err := saveCollection(path, mergeCollection(coll, g.acquired)); err != nil {
+		t.Fatalf("save the collection: %v", err)
+	}
+	merged, err := loadCollection(path)
+	if err != nil {
+		t.Fatalf("load the saved collection: %v", err)
+	}
+	for k, c := range colors[:*numColors] {
+		if want := coll[k] + res.Acquired[c]; merged[k] != want {
+			t.Errorf("%s: %d toys, want %d", c, merged[k], want)
+		}
+	}
+}
+
+func TestLoadCollectionUnknownColor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "collection.json")
+	if err := os.WriteFile(path, []byte(`{"Red": 1, "Gold": 2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCollection(path); err == nil || !strings.Contains(err.Error(), `"Gold"`) {
+		t.Errorf("load a collection with an unknown color: error %v, want one naming the color", err)
+	}
+}
+
+func TestRecommendLuckyColor(t *testing.T) {
+	old := colorProbs
+	t.Cleanup(func() { colorProbs = old })
+	// Yellow is the scarcest color of the collection but can never be drawn, Purple is the scarcest one that can.
+	coll := []int{5, 0, 1, 5, 5, 5, 5, 5, 5}
+	colorProbs = []float64{1, 0, 1, 1, 1, 1, 1, 1, 1}
+	if got := recommendLuckyColor(coll); got != 3 {
+		t.Errorf("recommended %s, want %s", colors[got-1], colors[2])
+	}
+}
+
+func TestPickLuckyColorFillGap(t *testing.T) {
+	old := colorProbs
+	t.Cleanup(func() { colorProbs = old })
+	path := filepath.Join(t.TempDir(), "collection.json")
+	tally := `{"Red": 4, "Yellow": 1, "Purple": 3, "Orange": 2, "Green": 1, "Cyan": 5, "Pink": 2, "Blue": 6, "Brown": 3}`
+	if err := os.WriteFile(path, []byte(tally), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	coll, err := loadCollection(path)
+	if err != nil {
+		t.Fatalf("load the collection: %v", err)
+	}
+	setFlag(t, "collection-in", path)
+	setFlag(t, "color", "fill-gap")
+	out := captureUI(t)
+	// Yellow and Green are tied for the scarcest color, Green is drawn more often.
+	colorProbs = []float64{1, 1, 1, 1, 2, 1, 1, 1, 1}
+	if !validLuckyColorSpec() {
+		t.Fatal("-color fill-gap with -collection-in was rejected")
+	}
+	if got := pickLuckyColor(coll); got != 5 {
+		t.Errorf("fill-gap picked %s, want %s", colors[got-1], colors[4])
+	}
+	if !strings.Contains(out.String(), "shortest on Green") {
+		t.Errorf("the choice of fill-gap is not announced:\n%s", out)
+	}
+}
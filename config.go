@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"runtime/debug"
+)
+
+// configPath loads the flags of a manifest written by -dump-config, and dumpConfig writes the resolved configuration
+// of the game as such a manifest. Flags given on the command line take precedence over the loaded ones, so that
+// dumping a run and loading the manifest back reproduces the run, seed included.
+var (
+	configPath = flag.String("config", "", "load the flags of a manifest written by -dump-config")
+	dumpConfig = flag.String("dump-config", "", "write the resolved configuration to this JSON manifest")
+)
+
+// configManifest is the JSON manifest of the resolved configuration of a game.
+// Only Flags is read back by -config; the other fields are derived from the flags and are there for reference.
+type configManifest struct {
+	Flags        map[string]string `json:"flags"`
+	Colors       []string          `json:"colors"`
+	Packages     []int             `json:"packages"`
+	Rewards      map[string]int    `json:"rewards"`
+	Acquired     map[string]int    `json:"acquired"`
+	Combinations [][]int           `json:"combinations"`
+}
+
+// manifestExcluded holds the flags that are never dumped to a manifest, as they are about manifests themselves,
+// do not play a game, or are already resolved into another flag, as -seed-from-string is into -seed.
+var manifestExcluded = map[string]bool{
+	"config":           true,
+	"dump-config":      true,
+	"selftest":         true,
+	"benchmark-config": true,
+	"seed-from-string": true,
+}
+
+// loadConfig function sets every flag of the manifest at path that was not given on the command line.
+func loadConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var m configManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	for name, value := range m.Flags {
+		if manifestExcluded[name] || flagSet(name) {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("parse %s: flag -%s: %w", path, name, err)
+		}
+	}
+	return nil
+}
+
+// newConfigManifest function builds the manifest of the resolved configuration.
+func newConfigManifest() configManifest {
+	m := configManifest{
+		Flags:        make(map[string]string),
+		Colors:       colors[:*numColors],
+		Packages:     packages,
+		Rewards:      make(map[string]int),
+		Acquired:     make(map[string]int),
+		Combinations: tripleCombination,
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		if !manifestExcluded[f.Name] {
+			m.Flags[f.Name] = f.Value.String()
+		}
+	})
+	for e, name := range eventNames {
+		m.Rewards[name] = eventRewardRules[e]
+		if n, ok := eventAcquired[e]; ok {
+			m.Acquired[name] = n
+		}
+	}
+	return m
+}
+
+// saveConfig function writes the manifest of the resolved configuration to path.
+func saveConfig(path string) error {
+	data, err := json.MarshalIndent(newConfigManifest(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// fingerprint function returns a one-line fingerprint of the session for bug reports: the seed, a hash of the resolved
+// configuration and the version of the program, e.g. "seed 994, config 8c0d5b1e, version (devel)". The manifest
+// encodes its maps with sorted keys, so that the same configuration always hashes the same.
+func fingerprint() string {
+	data, err := json.Marshal(newConfigManifest())
+	if err != nil {
+		die("fingerprint the configuration failed, %v", err)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(data)
+	version := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
+	}
+	return fmt.Sprintf("seed %d, config %08x, version %s", *seed, h.Sum32(), version)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDumpConfigRoundTrip(t *testing.T) {
+	isolateFlags(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	play := func() string {
+		var buf bytes.Buffer
+		useSeed(t, *seed)
+		playGame(newGame(2, 18), newTextRenderer(&buf, false))
+		return buf.String()
+	}
+	setFlag(t, "seed", "42")
+	setFlag(t, "anti-repeat", "true")
+	setFlag(t, "pair-greedy", "false")
+	if err := saveConfig(path); err != nil {
+		t.Fatalf("dump the configuration: %v", err)
+	}
+	want := play()
+
+	setFlag(t, "seed", "0")
+	setFlag(t, "anti-repeat", "false")
+	setFlag(t, "pair-greedy", "true")
+	if err := loadConfig(path); err != nil {
+		t.Fatalf("load the configuration: %v", err)
+	}
+	if *seed != 42 {
+		t.Fatalf("seed = %d after loading the configuration, want 42", *seed)
+	}
+	if got := play(); got != want {
+		t.Errorf("the reloaded configuration plays\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	useSeed(t, 77)
+	first, second := fingerprint(), fingerprint()
+	if first != second {
+		t.Errorf("the same configuration and seed fingerprint to %q and %q", first, second)
+	}
+	if !strings.HasPrefix(first, "seed 77, config ") {
+		t.Errorf("fingerprint = %q, want it to start with the seed", first)
+	}
+	setFlag(t, "pair-greedy", "false")
+	if changed := fingerprint(); changed == first {
+		t.Errorf("changing -pair-greedy leaves the fingerprint %q", changed)
+	}
+	setFlag(t, "pair-greedy", "true")
+	useSeed(t, 78)
+	if reseeded := fingerprint(); reseeded == first {
+		t.Errorf("changing the seed leaves the fingerprint %q", reseeded)
+	}
+}
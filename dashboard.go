@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// dashboard replaces the sections of the text output printed each turn with a single status line, rewritten in place
+// every turn: the turn, the board hash, the events of the turn, the toys acquired, the toys remaining and the score.
+// The status line needs a terminal; when stdout is not one, the full text output is printed instead.
+var dashboard = flag.Bool("dashboard", false, "print a single status line updated in place each turn, on a terminal")
+
+// DashboardRenderer renders each turn as a status line rewritten in place, and the outcome as the TextRenderer does.
+type DashboardRenderer struct {
+	*TextRenderer
+	turn   int
+	hash   string
+	events []string
+}
+
+// isTerminal function reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// newDashboardRenderer function returns a DashboardRenderer writing to w.
+func newDashboardRenderer(w io.Writer) *DashboardRenderer {
+	return &DashboardRenderer{TextRenderer: newTextRenderer(w, false)}
+}
+
+// Board method records the hash of the board of the current turn.
+func (r *DashboardRenderer) Board(board, placed []int) {
+	r.turn++
+	r.hash = boardHash(board)
+}
+
+// Events method records the events of the current turn.
+func (r *DashboardRenderer) Events(events []ev) {
+	r.events = r.events[:0]
+	for _, e := range events {
+		r.events = append(r.events, eventLabel(e.event))
+	}
+}
+
+// Acquired method rewrites the status line of the current turn.
+func (r *DashboardRenderer) Acquired(acq []int, remaining, score int) {
+	events := "no event"
+	if len(r.events) > 0 {
+		events = strings.Join(r.events, ", ")
+	}
+	_, _ = fmt.Fprintf(r.w, "\r\033[KTurn %d | board %s | %s | toys %d | remaining %d | score %d",
+		r.turn, r.hash, events, total(acq), remaining, score)
+}
+
+// Summary method ends the status line and prints the outcome of the game.
+func (r *DashboardRenderer) Summary(res gameResult) {
+	_, _ = fmt.Fprintln(r.w)
+	r.TextRenderer.Summary(res)
+}
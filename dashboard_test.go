@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDashboardStatusLine(t *testing.T) {
+	useSeed(t, 1)
+	g := newGame(9, 30)
+	copy(g.board, []int{1, 0, 3, 4, 5, 6, 7, 8, 2})
+	g.orderedEmptySlots, g.remaining, g.draw = emptySlots(g.board), 2, drawSequence(1)
+	// The Red toy drawn into the slot 1 makes a pair, which the hash of the board shows before it is resolved.
+	var buf bytes.Buffer
+	g.playTurn(newDashboardRenderer(&buf))
+	line := buf.String()
+	if !strings.HasPrefix(line, "\r\033[K") || strings.Contains(line, "\n") {
+		t.Errorf("status line = %q, want a single line rewritten in place", line)
+	}
+	for _, field := range []string{
+		"Turn 1",
+		"board " + boardHash([]int{1, 1, 3, 4, 5, 6, 7, 8, 2}),
+		eventLabel(eventOnePair),
+		fmt.Sprintf("toys %d", total(g.acquired)),
+		fmt.Sprintf("remaining %d", g.remaining),
+		fmt.Sprintf("score %d", g.score),
+	} {
+		if !strings.Contains(line, field) {
+			t.Errorf("status line = %q, want it to hold %q", line, field)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// demo runs a fully automated game that needs no input, narrating each event, e.g. for a terminal recording.
+// Unless -seed is given, the game is played with demoSeed, chosen because it triggers each of the five events.
+var (
+	demo      = flag.Bool("demo", false, "play an automated, narrated demo game")
+	demoDelay = flag.Duration("demo-delay", time.Second, "delay between the turns of the demo game")
+)
+
+const (
+	demoSeed       = 994
+	demoLuckyColor = 1
+	demoPackage    = 30
+)
+
+// eventExplanations holds a brief explanation of each event used to narrate the demo game, indexed by event type.
+// Each explanation is formatted with the points awarded by the event.
+var eventExplanations = []string{
+	"a toy of the lucky color was drawn, %d more toy to place",
+	"two toys of the same color are on the board, they are collected and %d more toy is placed",
+	"three toys of the same color lie in a line, they are collected and %d more toys are placed",
+	"the board is full of distinct colors, every toy is collected and %d more toys are placed",
+	"no toy is left on the board, %d more toys are placed",
+}
+
+// startDemo function introduces the demo game and returns its lucky color (1-based) and package size.
+func startDemo() (int, int) {
+	_, _ = fmt.Fprintf(ui, "Demo: %s is the lucky color and the package holds %d toys (seed %d)\n",
+		colors[demoLuckyColor-1], demoPackage, *seed)
+	return demoLuckyColor, demoPackage
+}
+
+// narrateDemo function explains the events of a turn of the demo game, then waits before the next turn.
+func narrateDemo(events []ev) {
+	for _, e := range events {
+		_, _ = fmt.Fprintf(ui, "Demo: %s, %s\n", eventLabel(e.event), fmt.Sprintf(eventExplanations[e.event], eventRewardRules[e.event]))
+	}
+	sleep(*demoDelay)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDemo(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, demoSeed)
+	setFlag(t, "demo", "true")
+	setFlag(t, "demo-delay", "0")
+	out := captureUI(t)
+	var board bytes.Buffer
+	interactive(newTextRenderer(&board, false))
+	narrated := 0
+	for _, e := range []int{eventLuckyStrike, eventAllDifferent, eventClear} {
+		if strings.Contains(out.String(), "Demo: "+eventLabel(e)+",") {
+			narrated++
+		}
+	}
+	if narrated == 0 {
+		t.Errorf("the demo narrates none of the key events:\n%s", out)
+	}
+}
+
+func TestDemoDelay(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, demoSeed)
+	setFlag(t, "demo", "true")
+	captureUI(t)
+	old := sleep
+	t.Cleanup(func() { sleep = old })
+	var slept []time.Duration
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	turns := 0
+	interactive(turnCounter{&turns})
+	if len(slept) != turns {
+		t.Errorf("the demo waited %d times over %d turns, want once per turn", len(slept), turns)
+	}
+	for _, d := range slept {
+		if d != time.Second {
+			t.Fatalf("the demo waited %v, want the default -demo-delay of 1s", d)
+		}
+	}
+}
+
+// turnCounter is a Renderer that counts the turns of a game, by the boards rendered.
+type turnCounter struct {
+	turns *int
+}
+
+func (r turnCounter) Board([]int, []int)       { *r.turns++ }
+func (r turnCounter) Events([]ev)              {}
+func (r turnCounter) Acquired([]int, int, int) {}
+func (r turnCounter) Summary(gameResult)       {}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// describe replaces the grid of the text output with sentences, read more easily by screen readers: each turn
+// describes the board row by row, e.g. "Top row: Red (new), empty, Blue.", then the events and the totals.
+var describe = flag.Bool("describe", false, "describe the board, the events and the totals in sentences instead of a grid")
+
+// rowNames holds the name of each row of the board in the descriptions, from top to bottom.
+var rowNames = [boardSize]string{"Top", "Middle", "Bottom"}
+
+// DescribeRenderer renders the game as plain descriptive sentences.
+type DescribeRenderer struct {
+	w io.Writer
+}
+
+// Board method describes the content of each row of the board, marking the toys placed this turn as new.
+func (r *DescribeRenderer) Board(board, placed []int) {
+	for row := range boardSize {
+		slots := make([]string, boardSize)
+		for col := range boardSize {
+			k := row*boardSize + col
+			switch {
+			case board[k] == 0:
+				slots[col] = "empty"
+			case slices.Contains(placed, k):
+				slots[col] = colors[board[k]-1] + " (new)"
+			default:
+				slots[col] = colors[board[k]-1]
+			}
+		}
+		_, _ = fmt.Fprintf(r.w, "%s row: %s.\n", rowNames[row], strings.Join(slots, ", "))
+	}
+}
+
+// Events method describes each event of the turn and its points.
+func (r *DescribeRenderer) Events(events []ev) {
+	if len(events) == 0 {
+		_, _ = fmt.Fprintln(r.w, "No event this turn.")
+	}
+	for _, e := range events {
+		_, _ = fmt.Fprintf(r.w, "%s fired, worth %s.\n", eventLabel(e.event), quantity(eventRewardRules[e.event], "point"))
+	}
+}
+
+// Acquired method describes the toys acquired so far, the toys that remain to be placed and the score.
+func (r *DescribeRenderer) Acquired(acq []int, remaining, score int) {
+	verb := "remain"
+	if remaining == 1 {
+		verb = "remains"
+	}
+	_, _ = fmt.Fprintf(r.w, "You have acquired %s. %s %s to be placed. Your score is %s.\n",
+		describeToys(acq), quantity(remaining, "toy"), verb, quantity(score, "point"))
+}
+
+// Summary method describes the outcome of the game.
+func (r *DescribeRenderer) Summary(res gameResult) {
+	acq := make([]int, *numColors)
+	for k, c := range colors[:*numColors] {
+		acq[k] = res.Acquired[c]
+	}
+	_, _ = fmt.Fprintf(r.w, "The game ended: %s. You have received %s: %s. Your final score is %s.\n",
+		res.TerminationReason, quantity(res.Total, "toy"), describeToys(acq), quantity(res.Score, "point"))
+}
+
+// describeToys function lists the acquired toys of acq, indexed by color (0-based), in the order of -sort-acquired,
+// e.g. "2 Red and 1 Blue", or "no toy".
+func describeToys(acq []int) string {
+	items := make([]string, 0)
+	for _, k := range acquiredOrder(acq) {
+		if acq[k] > 0 {
+			items = append(items, fmt.Sprintf("%d %s", acq[k], colors[k]))
+		}
+	}
+	switch len(items) {
+	case 0:
+		return "no toy"
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// quantity function returns n followed by noun, made plural unless n is 1, e.g. "1 point" or "3 points".
+func quantity(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
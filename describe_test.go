@@ -0,0 +1,18 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDescribeRendererBoard(t *testing.T) {
+	var buf bytes.Buffer
+	r := &DescribeRenderer{w: &buf}
+	r.Board([]int{1, 0, 8, 0, 2, 0, 3, 3, 0}, []int{0, 7})
+	want := "Top row: Red (new), empty, Blue.\n" +
+		"Middle row: empty, Yellow, empty.\n" +
+		"Bottom row: Purple, Purple (new), empty.\n"
+	if got := buf.String(); got != want {
+		t.Errorf("description =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// antiRepeat lowers the chance of drawing the color that was just placed: its draw weight is multiplied by antiRepeatDecay.
+var (
+	antiRepeat      = flag.Bool("anti-repeat", false, "make the color just placed less likely to be drawn next")
+	antiRepeatDecay = flag.Float64("anti-repeat-decay", 0.5, "draw weight factor of the color just placed under -anti-repeat, from 0 to 1")
+)
+
+// luckyBoost multiplies the draw weight of the lucky color, so that Lucky Color events are more frequent.
+// Every Lucky Color event gives one more toy to place, so a large boost can keep the game going for a very long time.
+var luckyBoost = flag.Float64("lucky-boost", 1, "draw weight factor of the lucky color")
+
+// finiteStock models a machine holding a finite stock of toys of each color: the odds of a color are scaled by its
+// stock, every draw takes a toy from the stock of its color, and a color whose stock is depleted can no longer be
+// drawn. The starting stock is given by stockSpec, either a single count for every color in play or one count per color
+// in palette order. See initStock.
+var (
+	finiteStock = flag.Bool("finite-stock", false, "draw from a finite stock of toys of each color")
+	stockSpec   = flag.String("stock", "10", "starting stock under -finite-stock: a count for every color, or one count per color")
+)
+
+// weightsSpec sets the draw weight of each color in play, in palette order, see parseWeights. The weights are
+// either positive ratios, such as "2,1,1,1,1" for five colors, or percentages summing to 100, such as
+// "40%,15%,15%,15%,15%".
+var weightsSpec = flag.String("weights", "", "draw weight of each color in palette order, as ratios or percentages")
+
+// colorProbs holds the normalized draw weights of -weights, indexed by color (0-based), or nil without -weights.
+var colorProbs []float64
+
+// initWeights function parses the draw weights of -weights.
+func initWeights() error {
+	p, err := parseWeights(*weightsSpec)
+	colorProbs = p
+	return err
+}
+
+// parseWeights function parses one weight for every color in play from spec, comma-separated in palette order, and
+// normalizes them into probabilities summing to 1. The weights are either all ratios or all percentages, suffixed
+// with %, which must sum to 100. Negative, infinite and NaN weights are rejected, as are weights that are all zero.
+func parseWeights(spec string) ([]float64, error) {
+	fields := strings.Split(spec, ",")
+	if len(fields) != *numColors {
+		return nil, fmt.Errorf("want %d weights, got %d", *numColors, len(fields))
+	}
+	percent := strings.HasSuffix(strings.TrimSpace(fields[0]), "%")
+	probs := make([]float64, len(fields))
+	sum := 0.0
+	for k, field := range fields {
+		field = strings.TrimSpace(field)
+		if strings.HasSuffix(field, "%") != percent {
+			return nil, fmt.Errorf("mixed ratios and percentages in %q", spec)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+		if err != nil || w < 0 || math.IsInf(w, 0) || math.IsNaN(w) {
+			return nil, fmt.Errorf("invalid weight %q", field)
+		}
+		probs[k] = w
+		sum += w
+	}
+	if sum == 0 {
+		return nil, fmt.Errorf("the weights are all zero")
+	}
+	if percent && math.Abs(sum-100) > 1e-9 {
+		return nil, fmt.Errorf("the percentages sum to %v, want 100", sum)
+	}
+	for k := range probs {
+		probs[k] /= sum
+	}
+	return probs, nil
+}
+
+// stock holds the toys left in the machine for each color in play, indexed by color (0-based), under -finite-stock.
+var stock []int
+
+// initStock function parses the starting stock of -stock.
+func initStock() error {
+	s, err := parseColorCounts(*stockSpec)
+	stock = s
+	return err
+}
+
+// parseColorCounts function parses a count for every color in play, indexed by color (0-based), from spec:
+// either a single count for every color, or comma-separated counts in palette order.
+func parseColorCounts(spec string) ([]int, error) {
+	fields := strings.Split(spec, ",")
+	if len(fields) != 1 && len(fields) != *numColors {
+		return nil, fmt.Errorf("want 1 or %d counts, got %d", *numColors, len(fields))
+	}
+	counts := make([]int, *numColors)
+	for k := range counts {
+		field := fields[0]
+		if len(fields) > 1 {
+			field = fields[k]
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid count %q", field)
+		}
+		counts[k] = n
+	}
+	return counts, nil
+}
+
+// stockDepleted function reports whether no toy is left to draw under -finite-stock.
+func stockDepleted() bool {
+	if !*finiteStock {
+		return false
+	}
+	for _, n := range stock {
+		if n > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// lastDrawn is the color (1-based) of the last toy drawn, or 0 before the first draw.
+var lastDrawn int
+
+// colorWeights function returns the draw weight of each color in play, indexed by color (0-based), for the lucky color
+// luckyColor (1-based). The factors of -weights, -lucky-boost, -anti-repeat and -finite-stock multiply each other.
+func colorWeights(luckyColor int) []float64 {
+	weights := make([]float64, *numColors)
+	for k := range weights {
+		weights[k] = 1
+		if colorProbs != nil {
+			weights[k] = colorProbs[k]
+		}
+	}
+	if luckyColor > 0 {
+		weights[luckyColor-1] *= *luckyBoost
+	}
+	if *antiRepeat && lastDrawn > 0 {
+		weights[lastDrawn-1] *= *antiRepeatDecay
+	}
+	if *finiteStock {
+		for k, n := range stock {
+			weights[k] *= float64(n)
+		}
+	}
+	return weights
+}
+
+// weightedDraws function reports whether the draws are weighted. Uniform draws use a single rng.IntN call per draw,
+// exactly as before weights existed, so that seeds recorded with uniform draws keep reproducing the same games.
+func weightedDraws() bool {
+	return *antiRepeat || *luckyBoost != 1 || *finiteStock || colorProbs != nil
+}
+
+// drawColor function draws the color (1-based) of the next toy from rng, according to colorWeights.
+// It returns 0 when the stock is depleted under -finite-stock.
+func drawColor(luckyColor int) int {
+	if stockDepleted() {
+		return 0
+	}
+	c := 0
+	if !weightedDraws() {
+		c = rng.IntN(*numColors) + 1
+	} else {
+		c = weightedIndex(colorWeights(luckyColor), rng.Float64()) + 1
+	}
+	if *finiteStock {
+		stock[c-1]--
+	}
+	lastDrawn = c
+	return c
+}
+
+// weightedIndex function returns the index picked by x, in [0, 1), from weights read as a cumulative distribution.
+func weightedIndex(weights []float64, x float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	x *= total
+	for k, w := range weights {
+		if x < w {
+			return k
+		}
+		x -= w
+	}
+	// Rounding can leave x just above the last weight; fall back on the last color with a positive weight.
+	for k := len(weights) - 1; k > 0; k-- {
+		if weights[k] > 0 {
+			return k
+		}
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDrawColorNumColors(t *testing.T) {
+	useSeed(t, 1)
+	setFlag(t, "num-colors", "5")
+	for range 10000 {
+		if c := drawColor(1); c < 1 || c > 5 {
+			t.Fatalf("drew color %d, want 1 to 5", c)
+		}
+	}
+}
+
+func TestDrawColorAntiRepeat(t *testing.T) {
+	useSeed(t, 1)
+	setFlag(t, "anti-repeat", "true")
+	const draws = 20000
+	repeats, prev := 0, 0
+	for range draws {
+		c := drawColor(0)
+		if c == prev {
+			repeats++
+		}
+		prev = c
+	}
+	// Uniform draws repeat the previous color once in 9 draws, halving its weight brings that down to about 1 in 17.
+	if rate, uniform := float64(repeats)/draws, 1/float64(*numColors); rate > 0.8*uniform {
+		t.Errorf("immediate repeats rate = %.3f, want well below the uniform %.3f", rate, uniform)
+	}
+}
+
+func TestDrawColorLuckyBoost(t *testing.T) {
+	useSeed(t, 1)
+	setFlag(t, "lucky-boost", "50")
+	histogram := make([]int, *numColors+1)
+	for range 10000 {
+		histogram[drawColor(3)]++
+	}
+	for c, n := range histogram {
+		if c != 3 && n >= histogram[3]/10 {
+			t.Errorf("color %d drawn %d times against %d for the boosted lucky color", c, n, histogram[3])
+		}
+	}
+}
+
+func TestDrawColorFiniteStock(t *testing.T) {
+	useSeed(t, 1)
+	useStock(t, "1,5,5,5,5,5,5,5,5")
+	drawn := make([]int, *numColors+1)
+	for range 100 {
+		drawn[drawColor(0)]++
+	}
+	if drawn[1] != 1 {
+		t.Errorf("Red, with a stock of 1, drawn %d times, want once", drawn[1])
+	}
+	if drawn[0] != 100-41 {
+		t.Errorf("%d draws from the depleted stock, want %d", drawn[0], 100-41)
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	setFlag(t, "num-colors", "4")
+	for _, tc := range []struct {
+		spec string
+		want []float64
+	}{
+		{"2,1,1,0", []float64{0.5, 0.25, 0.25, 0}},
+		{"3, 3, 1, 1", []float64{0.375, 0.375, 0.125, 0.125}},
+		{"40%,30%,20.5%,9.5%", []float64{0.4, 0.3, 0.205, 0.095}},
+	} {
+		probs, err := parseWeights(tc.spec)
+		if err != nil {
+			t.Errorf("-weights=%s: %v", tc.spec, err)
+			continue
+		}
+		for k, p := range probs {
+			if math.Abs(p-tc.want[k]) > 1e-12 {
+				t.Errorf("-weights=%s: probabilities %v, want %v", tc.spec, probs, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseWeightsInvalid(t *testing.T) {
+	setFlag(t, "num-colors", "4")
+	for _, spec := range []string{
+		"1,1,1",           // too few
+		"1,-1,1,1",        // negative
+		"0,0,0,0",         // all zero
+		"1,NaN,1,1",       // not a number
+		"1,Inf,1,1",       // infinite
+		"50%,30%,10%,5%",  // not summing to 100
+		"50%,30%,10%,10",  // mixed
+		"1,one,1,1",       // not a number
+		"25%,25%,NaN%,0%", // not a number, as a percentage
+	} {
+		if probs, err := parseWeights(spec); err == nil {
+			t.Errorf("-weights=%s parsed into %v, want an error", spec, probs)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// game holds the state of a game in progress.
+type game struct {
+	luckyColor        int
+	packageSize       int
+	remaining         int
+	board             []int
+	acquired          []int
+	orderedEmptySlots []int
+	stats             gameStats
+	turn              int
+	// score is the total of the points awarded by the events of the game.
+	score int
+	// odds holds the probability of drawing each color at the start of the game, indexed by color (0-based).
+	odds []float64
+	// draw returns the color (1-based) of the next toy placed on the board, and depleted reports whether draw has no
+	// toy left to return under -finite-stock.
+	draw     func() int
+	depleted func() bool
+	// roll returns a number in [0, 1) for the wild draws of -wild-chance, see wildDraw.
+	roll func() float64
+	// cancelled is set once the player has interrupted the game.
+	cancelled bool
+	// bonus and bonusSlots are the bonus board and its empty slots under -bonus-board, see resolveBonusBoard.
+	bonus      []int
+	bonusSlots []int
+	// silent is set on the copies played by the analyses, which send no webhook notification.
+	silent bool
+	// confirmReset, when set, is called before the board is reset by a Family Portrait; returning false cancels the game.
+	confirmReset func() bool
+}
+
+// terminationReason tells why a game ended.
+type terminationReason int
+
+// Constants representing the reasons a game ends.
+const (
+	endToysExhausted terminationReason = iota
+	endStockDepleted
+	endCancelled
+	endTargetsReached
+	endBoxFull
+)
+
+// terminationDesc holds the human-readable description of each termination reason, indexed by reason.
+var terminationDesc = []string{"toys exhausted", "stock depleted", "cancelled", "targets reached", "box full"}
+
+// String method returns the description of the termination reason.
+func (t terminationReason) String() string {
+	return terminationDesc[t]
+}
+
+// MarshalText method encodes the termination reason as its description, e.g. in the JSON output.
+func (t terminationReason) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// newGame function starts a game with the given lucky color (1-based) and package size, drawing its toys with drawColor.
+func newGame(luckyColor, packageSize int) *game {
+	g := &game{
+		luckyColor:  luckyColor,
+		packageSize: packageSize,
+		remaining:   packageSize,
+		board:       make([]int, len(initialOrderedSlots)),
+		acquired:    make([]int, *numColors),
+		odds:        drawOdds(luckyColor),
+	}
+	if initialBoard != nil {
+		copy(g.board, initialBoard)
+	}
+	g.orderedEmptySlots = emptySlots(g.board)
+	if *bonusBoard {
+		g.bonus = make([]int, len(initialOrderedSlots))
+		g.bonusSlots = emptySlots(g.bonus)
+	}
+	g.draw = func() int { return drawColor(luckyColor) }
+	g.roll = func() float64 { return rng.Float64() }
+	g.depleted = stockDepleted
+	return g
+}
+
+// playTurn method plays one turn of the game: it places toys on the board, checks the board for events and
+// handles them, rendering the progress with r. It returns the events reported for the turn.
+func (g *game) playTurn(r Renderer) []ev {
+	g.turn++
+	events := make([]ev, 0)
+	before, slots := g.remaining, g.orderedEmptySlots
+	g.remaining, events, g.orderedEmptySlots = placeInSlot(g.board, g.orderedEmptySlots, events, g.remaining, g.luckyColor, g.draw, g.roll)
+	placed := before - g.remaining
+	g.stats.observePlacement(g.board)
+	placedSlots := slices.DeleteFunc(slices.Clone(slots), func(k int) bool { return g.board[k] == 0 })
+	r.Board(g.board, placedSlots)
+	stacked := stackedLuckyToys(g.board, placedSlots, g.luckyColor)
+	reset, occupied := false, occupiedSlots(g.board)
+	g.stats.observeOverlaps(overlappingSlots(g.board))
+	events, g.orderedEmptySlots, reset = checkBoard(g.board, g.orderedEmptySlots, events)
+	freed := occupied - occupiedSlots(g.board)
+	g.stats.observeFreed(freed)
+	if nearClearTiers != nil {
+		events = detectNearClear(events, freed)
+	}
+	if *noEventStacking {
+		events = unstackEvents(events, stacked, g.luckyColor, g.acquired)
+	}
+	if len(eventPointsChances) > 0 {
+		events = applyEventPointsChances(events, g.acquired)
+	}
+	if reset {
+		if g.confirmReset != nil && !g.confirmReset() {
+			g.cancelled = true
+		}
+		g.orderedEmptySlots = resetBoard(g.board)
+	}
+	if *bonusBoard {
+		events = g.resolveBonusBoard(events)
+	}
+	points := handleEvents(events, g.acquired, g.remaining) - g.remaining
+	g.score += points
+	if !*strictCount {
+		g.remaining += points
+	}
+	reported := reportedEvents(events)
+	g.stats.observeTurn(reported, placed)
+	g.stats.turns = append(g.stats.turns, newTurnRecord(g.turn, placed, reported, g.remaining, g.acquired))
+	if !g.silent {
+		for _, e := range reported {
+			slog.Debug("event", "turn", g.turn, "event", eventNames[e.event], "reward", eventRewardRules[e.event])
+		}
+		notifyRareEvents(reported, g.board, g.acquired, g.remaining)
+	}
+	r.Events(reported)
+	r.Acquired(g.acquired, g.remaining, g.score)
+	return reported
+}
+
+// over method reports whether the game is over: no toy remains to be placed, none is left to draw,
+// the player cancelled the game, every target is met under -stop-at-targets, or the box is full under -max-toys.
+func (g *game) over() bool {
+	return g.remaining <= 0 || g.depleted() || g.cancelled || (*stopAtTargets && targetsMet(g.acquired)) || g.boxFull()
+}
+
+// boxFull method reports whether the toys acquired, counting those left on the board, reach -max-toys.
+func (g *game) boxFull() bool {
+	return *maxToys > 0 && total(g.acquired)+occupiedSlots(g.board) >= *maxToys
+}
+
+// terminationReason method returns why the game ended, once it is over.
+func (g *game) terminationReason() terminationReason {
+	switch {
+	case g.cancelled:
+		return endCancelled
+	case *stopAtTargets && targetsMet(g.acquired):
+		return endTargetsReached
+	case g.boxFull():
+		return endBoxFull
+	case g.remaining > 0:
+		return endStockDepleted
+	}
+	return endToysExhausted
+}
+
+// finish method acquires the toys left on the board once the game is over, and returns the outcome of the game.
+// Under -leftovers-require-match, the toys of a color that no event acquired are left out.
+func (g *game) finish() gameResult {
+	matched := slices.Clone(g.acquired)
+	for _, v := range g.board {
+		if v > 0 && (!*leftoversRequireMatch || matched[v-1] > 0) {
+			g.acquired[v-1] += 1
+		}
+	}
+	res := newGameResult(g.luckyColor, g.packageSize, g.acquired, g.stats)
+	res.Score = g.score
+	res.TerminationReason = g.terminationReason()
+	res.Rarity = rarityScore(g.acquired, g.odds)
+	return res
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerminationReason(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		setup func(t *testing.T)
+		play  func(g *game)
+		want  terminationReason
+	}{
+		{name: "toys exhausted", want: endToysExhausted},
+		{name: "stock depleted", setup: func(t *testing.T) { useStock(t, "1") }, want: endStockDepleted},
+		{name: "cancelled", play: func(g *game) { g.cancelled = true }, want: endCancelled},
+		{name: "targets reached", setup: func(t *testing.T) {
+			setFlag(t, "stop-at-targets", "true")
+			old := targets
+			t.Cleanup(func() { targets = old })
+			targets = []int{1, 0, 0, 0, 0, 0, 0, 0, 0}
+		}, want: endTargetsReached},
+		{name: "box full", setup: func(t *testing.T) { setFlag(t, "max-toys", "5") }, want: endBoxFull},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			useSeed(t, 1)
+			if tc.setup != nil {
+				tc.setup(t)
+			}
+			g := newGame(1, 30)
+			g.playTurn(discardRenderer{})
+			if tc.play != nil {
+				tc.play(g)
+			}
+			if res := playGame(g, discardRenderer{}); res.TerminationReason != tc.want {
+				t.Errorf("the game ended with %q, want %q", res.TerminationReason, tc.want)
+			}
+		})
+	}
+}
+
+func TestNoEventStacking(t *testing.T) {
+	// The lucky Red toy placed in the slot 2 completes the top row.
+	play := func() (*game, []ev) {
+		g := newGame(1, 30)
+		copy(g.board, []int{1, 1, 0, 3, 4, 5, 6, 7, 8})
+		g.orderedEmptySlots, g.remaining, g.draw = emptySlots(g.board), 1, drawSequence(1)
+		return g, g.playTurn(discardRenderer{})
+	}
+	stacked, stackedEvents := play()
+	setFlag(t, "no-event-stacking", "true")
+	single, singleEvents := play()
+	if want := []int{eventLuckyColor, eventLuckyStrike}; !slices.Equal(eventTypes(stackedEvents), want) {
+		t.Errorf("stacked events = %v, want %v", eventTypes(stackedEvents), want)
+	}
+	if want := []int{eventLuckyStrike}; !slices.Equal(eventTypes(singleEvents), want) {
+		t.Errorf("events without stacking = %v, want %v", eventTypes(singleEvents), want)
+	}
+	if want := single.score + eventRewardRules[eventLuckyColor]; stacked.score != want {
+		t.Errorf("stacked score = %d, want %d, the score without stacking plus the Lucky Color", stacked.score, want)
+	}
+}
+
+func TestNoEventStackingHigherLuckyColor(t *testing.T) {
+	saveEvents(t)
+	setFlag(t, "no-event-stacking", "true")
+	// As under -lucky-scale with the largest package, Lucky Color outweighs Lucky Strike.
+	eventRewardRules[eventLuckyColor] = 5
+	g := newGame(1, 30)
+	copy(g.board, []int{1, 1, 0, 3, 4, 5, 6, 7, 8})
+	g.orderedEmptySlots, g.remaining, g.draw = emptySlots(g.board), 1, drawSequence(1)
+	events := g.playTurn(discardRenderer{})
+	if want := []int{eventLuckyColor}; !slices.Equal(eventTypes(events), want) {
+		t.Errorf("events = %v, want the Lucky Strike dropped, %v", eventTypes(events), want)
+	}
+	if g.score != 5 {
+		t.Errorf("score = %d, want the 5 points of Lucky Color", g.score)
+	}
+	if g.acquired[0] < 3 {
+		t.Errorf("acquired %v, want the 3 Red toys of the dropped Lucky Strike", g.acquired)
+	}
+}
+
+func TestMaxPairsPerTurn(t *testing.T) {
+	useSeed(t, 1)
+	g := newGame(9, 30)
+	copy(g.board, []int{1, 2, 0, 3, 4, 5, 6, 7, 0})
+	// The Red toy drawn into the slot 2 and the Yellow one drawn into the slot 8 make two pairs in the same turn.
+	g.orderedEmptySlots, g.remaining, g.draw = emptySlots(g.board), 2, drawSequence(1, 2)
+	events := g.playTurn(discardRenderer{})
+	if want := []int{eventOnePair, eventOnePair}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want %v", eventTypes(events), want)
+	}
+	if res := g.finish(); res.MaxPairsPerTurn != 2 {
+		t.Errorf("most pairs in a turn = %d, want 2", res.MaxPairsPerTurn)
+	}
+}
+
+func TestLeftoversRequireMatch(t *testing.T) {
+	useSeed(t, 1)
+	setFlag(t, "leftovers-require-match", "true")
+	g := newGame(9, 30)
+	g.acquired[0] = 2
+	g.remaining = 0
+	// Red was acquired by an event and Yellow never was.
+	copy(g.board, []int{1, 2, 0, 0, 0, 0, 0, 0, 0})
+	res := g.finish()
+	if res.Acquired["Red"] != 3 {
+		t.Errorf("acquired %d Red, want the leftover kept on top of the 2 matched", res.Acquired["Red"])
+	}
+	if res.Acquired["Yellow"] != 0 {
+		t.Errorf("acquired %d Yellow, want the leftover of a color never matched left out", res.Acquired["Yellow"])
+	}
+}
+
+func TestMaxToysStopsAtCap(t *testing.T) {
+	useSeed(t, 3)
+	setFlag(t, "max-toys", "12")
+	g := newGame(1, 30)
+	for !g.over() {
+		if n := total(g.acquired) + occupiedSlots(g.board); n >= 12 {
+			t.Fatalf("turn %d: %d toys in the box but the game goes on", g.turn, n)
+		}
+		g.playTurn(discardRenderer{})
+	}
+	res := g.finish()
+	if res.TerminationReason != endBoxFull || res.Total < 12 {
+		t.Errorf("the game ended with %q and %d toys, want %q with at least 12", res.TerminationReason, res.Total, endBoxFull)
+	}
+	if g.remaining <= 0 {
+		t.Error("no toy remained to be placed, want the cap to end the game first")
+	}
+}
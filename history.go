@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// history prints a turn-by-turn table of the game once it is over, and historyCSV writes the same table as CSV.
+var (
+	history    = flag.Bool("history", false, "print a turn-by-turn history table at the end of the game")
+	historyCSV = flag.String("history-csv", "", "write the turn-by-turn history to this CSV file")
+)
+
+// turnRecord holds what happened during a turn: the toys placed, the events, the points they awarded,
+// the toys they acquired, the toys that remained to be placed afterwards and the toys acquired so far by color.
+type turnRecord struct {
+	turn      int
+	placed    int
+	events    []string
+	points    int
+	toys      int
+	remaining int
+	acquired  []int
+}
+
+// historyHeader is the header of the history table.
+var historyHeader = []string{"Turn", "Placed", "Events", "Points", "Toys", "Remaining"}
+
+// newTurnRecord function builds the record of a turn from the toys placed, its events, the remaining toys and
+// the toys acquired so far, indexed by color (0-based).
+func newTurnRecord(turn, placed int, events []ev, remaining int, acq []int) turnRecord {
+	rec := turnRecord{
+		turn:      turn,
+		placed:    placed,
+		events:    make([]string, 0, len(events)),
+		remaining: remaining,
+		acquired:  slices.Clone(acq),
+	}
+	for _, e := range events {
+		rec.events = append(rec.events, eventDesc[e.event])
+		rec.points += eventRewardRules[e.event]
+		for _, v := range e.acquired {
+			rec.toys += v
+		}
+	}
+	return rec
+}
+
+// row method returns the cells of the record in the order of historyHeader.
+func (rec turnRecord) row() []string {
+	return []string{
+		strconv.Itoa(rec.turn),
+		strconv.Itoa(rec.placed),
+		strings.Join(rec.events, ", "),
+		strconv.Itoa(rec.points),
+		strconv.Itoa(rec.toys),
+		strconv.Itoa(rec.remaining),
+	}
+}
+
+// printHistory function prints the records as an aligned table, one row per turn.
+func printHistory(w io.Writer, records []turnRecord) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "========== history ==========")
+	_, _ = fmt.Fprintln(tw, strings.Join(historyHeader, "\t"))
+	for _, rec := range records {
+		_, _ = fmt.Fprintln(tw, strings.Join(rec.row(), "\t"))
+	}
+	_ = tw.Flush()
+}
+
+// writeHistoryCSV function writes the records to path as CSV, with a header row.
+func writeHistoryCSV(path string, records []turnRecord) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write(historyHeader)
+	for _, rec := range records {
+		_ = w.Write(rec.row())
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
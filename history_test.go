@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistoryRowPerTurn(t *testing.T) {
+	useSeed(t, 3)
+	g := newGame(1, 18)
+	playGame(g, discardRenderer{})
+	var buf bytes.Buffer
+	printHistory(&buf, g.stats.turns)
+	// The title and the header precede the rows.
+	if rows := strings.Count(buf.String(), "\n") - 2; rows != g.turn {
+		t.Errorf("the table holds %d rows, want one per turn, %d", rows, g.turn)
+	}
+	path := filepath.Join(t.TempDir(), "history.csv")
+	if err := writeHistoryCSV(path, g.stats.turns); err != nil {
+		t.Fatalf("write the history: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read the history: %v", err)
+	}
+	if rows := len(records) - 1; rows != g.turn {
+		t.Errorf("the CSV holds %d rows, want one per turn, %d", rows, g.turn)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// logLevel and logFormat configure the structured logs written to stderr, apart from the game output: the start of
+// the game and its end at the info level, each event at the debug level, and failures such as those of -webhook at
+// the warn level and above.
+var (
+	logLevel  = flag.String("log-level", "warn", "minimum level of the logs written to stderr: debug, info, warn or error")
+	logFormat = flag.String("log-format", "text", "format of the logs written to stderr: text or json")
+)
+
+// setupLogging function makes the logger configured by -log-level and -log-format the default logger.
+func setupLogging() error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		return fmt.Errorf("-log-level must be debug, info, warn or error, got %q", *logLevel)
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	switch *logFormat {
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, opts)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, opts)))
+	default:
+		return fmt.Errorf("-log-format must be text or json, got %q", *logFormat)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestGameStartRecord(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, demoSeed)
+	setFlag(t, "demo", "true")
+	setFlag(t, "demo-delay", "0")
+	captureUI(t)
+	var logs bytes.Buffer
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	t.Cleanup(func() { slog.SetDefault(old) })
+	interactive(discardRenderer{})
+
+	dec := json.NewDecoder(&logs)
+	for dec.More() {
+		var record map[string]any
+		if err := dec.Decode(&record); err != nil {
+			t.Fatalf("decode the logs: %v", err)
+		}
+		if record["msg"] != "game start" {
+			continue
+		}
+		if record["level"] != "INFO" || record["seed"] != float64(demoSeed) ||
+			record["lucky_color"] != colors[demoLuckyColor-1] || record["package"] != float64(demoPackage) {
+			t.Errorf("game start record = %v, want seed %d, lucky color %s and package %d at the info level",
+				record, demoSeed, colors[demoLuckyColor-1], demoPackage)
+		}
+		return
+	}
+	t.Error("no game start record was logged")
+}
+
+func TestSetupLoggingInvalid(t *testing.T) {
+	setFlag(t, "log-level", "loud")
+	if err := setupLogging(); err == nil {
+		t.Error("-log-level loud was accepted")
+	}
+	setFlag(t, "log-level", "info")
+	setFlag(t, "log-format", "xml")
+	if err := setupLogging(); err == nil {
+		t.Error("-log-format xml was accepted")
+	}
+}
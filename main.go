@@ -1,13 +1,122 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/manifoldco/promptui"
+	"io"
+	"log/slog"
 	"math/rand/v2"
 	"os"
-	"sort"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// format selects the Renderer used for the output of the game, see newRenderer.
+// With the json format, the introduction, the prompts and the selections are written to stderr so that stdout
+// can be piped into jq.
+var format = flag.String("format", "text", "output format: text or json")
+
+// eventsCompact switches the text output to a single "Events: ..." line per turn instead of the banner format.
+var eventsCompact = flag.Bool("events-compact", false, "print each turn's events on a single line")
+
+// summaryOnlyJSON suppresses the per-turn JSON objects so that the output is a single JSON summary object.
+// It implies -format json.
+var summaryOnlyJSON = flag.Bool("summary-only-json", false, "print only the final summary, as a single JSON object")
+
+// numColors restricts the draws, the lucky color and the summaries to the first numColors colors.
+// It defaults to 9, the number of colors drawn before the flag existed.
+var numColors = flag.Int("num-colors", 9, "number of colors in play, from 2 up to the number of colors")
+
+// noClearEvent and noFamilyEvent disable the Clear The Board and Family Portrait events, and reallocateTo names
+// the event that receives their points instead. See configureEvents.
+var (
+	noClearEvent  = flag.Bool("no-clear-event", false, "disable the Clear The Board event")
+	noFamilyEvent = flag.Bool("no-family-event", false, "disable the Family Portrait event")
+	reallocateTo  = flag.String("reallocate-to", "", "give the points of disabled events to this event (one-pair or lucky-strike)")
+)
+
+// luckyScale scales the Lucky Color reward with the selected package, see luckyColorScale.
+var luckyScale = flag.Bool("lucky-scale", false, "scale the Lucky Color reward with the package size")
+
+// pairGreedy resolves as many pairs of a color as possible per turn; when false, at most one pair per color. See checkBoard.
+var pairGreedy = flag.Bool("pair-greedy", true, "pair as many toys of a color as possible per turn, instead of one pair per color")
+
+// gravity lets the toys left on the board fall to the bottom of their column once the matches are cleared, as they do
+// in the physical machine; new toys then fill the board from the bottom up. See applyGravity and emptySlots.
+var gravity = flag.Bool("gravity", false, "let the toys fall to the bottom of their column after each match")
+
+// shuffleSlots shuffles the order in which the empty slots are filled, once per game, using the seed of the game.
+var shuffleSlots = flag.Bool("shuffle-slots", false, "fill the empty slots in an order shuffled once per game")
+
+// strictCount makes a game place exactly as many toys as the package holds.
+// By default every point awarded by an event is one more toy to place, so a game places the package size plus the
+// points of all its events. Under -strict-count events are still scored, but their points no longer add toys to place.
+var strictCount = flag.Bool("strict-count", false, "place exactly the package size in toys, without extra toys for points")
+
+// maxToys models a prize box of limited capacity: the game ends once the toys acquired, counting those left on the
+// board, reach that many. The turn that fills the box is played out, so the total can exceed the capacity. Zero means
+// no capacity.
+var maxToys = flag.Int("max-toys", 0, "end the game once the toys acquired, counting those on the board, reach this many (0 for no cap)")
+
+// leftoversRequireMatch keeps the toys left on the board at the end of the game only for the colors the events of the
+// game already acquired, as if only the colors matched at least once were kept. See game.finish.
+var leftoversRequireMatch = flag.Bool("leftovers-require-match", false, "keep the toys left on the board at the end only for colors acquired by an event")
+
+// maxEventsPerTurn caps the number of lines and pairs resolved per turn, see checkBoard. Zero means no cap.
+var maxEventsPerTurn = flag.Int("max-events-per-turn", 0, "resolve at most this many lines and pairs per turn, deferring the rest (0 for no cap)")
+
+// noEventStacking counts a toy of the lucky color that completes a line during the turn it is placed only once.
+// By default the events stack: the toy fires Lucky Color when it is drawn and its line fires Lucky Strike, and both
+// award their points. Under -no-event-stacking only the event with the higher reward counts, the Lucky Strike on a
+// tie: usually the Lucky Color of such a toy is dropped, but under -lucky-scale or a multiplier card of lucky-color the
+// Lucky Strike may be dropped instead, its toys still collected. See unstackEvents.
+var noEventStacking = flag.Bool("no-event-stacking", false, "drop the Lucky Color of a lucky toy that completes a line the turn it is placed")
+
+// pauseOnReset explains the Family Portrait and waits for the player to press Enter before the board is reset,
+// in interactive games. See confirmReset.
+var pauseOnReset = flag.Bool("pause-on-reset", false, "explain and wait for Enter before the board is reset by a Family Portrait")
+
+// autoContinueAfter stops pausing between the turns of an interactive game once that many turns in a row fired no
+// event: the game advances on its own until a turn fires an event, then pauses again. Zero always pauses.
+var autoContinueAfter = flag.Int("auto-continue-after", 0, "stop pausing after this many turns in a row without events, until the next event (0 to always pause)")
+
+// holdSeconds keeps the final summary on screen for that many seconds before the program exits, e.g. to pace a kiosk
+// running games in a loop. The hold is skipped under -quiet. See holdSummary.
+var holdSeconds = flag.Int("hold-seconds", 0, "keep the final summary on screen this many seconds before exiting")
+
+// disabledEvents is the set of events that award no points and are not reported, see configureEvents.
+var disabledEvents = map[int]bool{}
+
+// reallocatedPoints holds the points of the disabled events under -reallocate-to, by event type, awarded to the
+// target event on the turns the disabled event fires. See configureEvents.
+var reallocatedPoints = map[int]int{}
+
+// helpRules prints the rules and the configuration, as the "h" command does during the game, instead of playing.
+var helpRules = flag.Bool("help-rules", false, "print the rules, the event rewards and the configuration, then exit")
+
+// seed seeds the generator behind every draw of the game, so that a game can be reproduced. Zero picks a seed from the clock.
+var seed = flag.Uint64("seed", 0, "seed of the random draws (0 for a random seed)")
+
+// verbose prints details meant for reproducing and debugging games, such as the seed.
+var verbose = flag.Bool("v", false, "print verbose details, such as the seed of the game")
+
+// rng is the generator behind every draw of the game, seeded by newRNG.
+var rng *rand.Rand
+
+// now and sleep are the clock behind the default seed, the think times of -time-turns, the delay of -demo-delay and
+// the hold of -hold-seconds, replaceable so that they can be made deterministic.
+var (
+	now   = time.Now
+	sleep = time.Sleep
+)
+
+// ui is where the introduction, the prompts, the selections and any other text not rendered by the Renderer are written.
+var ui io.WriteCloser = os.Stdout
+
 // Constants representing different colors.
 // The values range from 1 to 10, starting with Red as 1.
 var colors = []string{"Red", "Yellow", "Purple", "Orange", "Green", "Cyan", "Pink", "Blue", "Brown", "Magenta"}
@@ -27,6 +136,9 @@ const (
 // This slice is used to provide a human-readable description of the events when printing or displaying event information.
 var eventDesc = []string{"Lucky Color", "One Pair", "Lucky Strike", "Family Portrait", "Clear The Board"}
 
+// eventNames holds the names used to refer to the events on the command line, indexed by event type.
+var eventNames = []string{"lucky-color", "one-pair", "lucky-strike", "family-portrait", "clear-the-board"}
+
 type ev struct {
 	acquired map[int]int
 	event    int
@@ -54,8 +166,9 @@ var eventRewardRules = map[int]int{
 	eventClear:        5,
 }
 
-// tripleCombination defines a 2D slice where each inner slice represents
-// a combination of three indices that form a "triple combination" in a game or puzzle.
+// tripleCombination holds the slots of the lines of three toys on the board: the columns, the rows and the diagonals.
+// Under -toroidal or -no-diagonals, the lines are generated from linePatterns instead, see linePlacements; the slots of
+// each line are in order along the line.
 var tripleCombination = [][]int{
 	// The first set of combinations (vertical lines in a 3x3 grid).
 	{0, 3, 6},
@@ -69,93 +182,594 @@ var tripleCombination = [][]int{
 
 	// The third set of combinations (diagonals in a 3x3 grid).
 	{0, 4, 8},
-	{2, 3, 6},
+	{2, 4, 6},
 }
 
 // packages is a slice that represents the number of toys in different packs.
 // Each integer corresponds to a specific pack size, for example, 9, 18, and 35 toys per pack.
 var packages = []int{9, 18, 30}
 
+// luckyColorScale defines, for each entry of packages, the toys acquired and the points awarded by the Lucky Color event
+// when -lucky-scale is set. Without the flag the Lucky Color reward is flat, as given by eventAcquired and eventRewardRules.
+var luckyColorScale = map[int]struct{ acquired, points int }{
+	9:  {0, 1},
+	18: {1, 1},
+	30: {1, 2},
+}
+
 var initialOrderedSlots = []int{0, 1, 2, 3, 4, 5, 6, 7, 8}
 
-// die is a utility function that prints an error message and exits the program with a non-zero status.
+// slotOrder is the order in which placeInSlot fills the empty slots, see emptySlots. It is initialOrderedSlots
+// unless -shuffle-slots shuffles it once at the start of the game.
+var slotOrder = initialOrderedSlots
+
+// boardSize is the number of slots in each row and each column of the board.
+const boardSize = 3
+
+// die is a utility function that prints an error message to stderr and exits the program with a non-zero status,
+// so that the error never mixes with the game output, e.g. the JSON object of -summary-only-json.
 // The msg parameter is a formatted string, and args are the arguments to format the string.
 func die(msg string, args ...any) {
-	fmt.Printf(msg+"\n", args...)
+	_, _ = fmt.Fprintf(os.Stderr, msg+"\n", args...)
 	os.Exit(1)
 }
 
 func main() {
-	interactive()
+	flag.Usage = usage
+	flag.Parse()
+	if *selftest {
+		runSelftest()
+		return
+	}
+	if *seedPhrase != "" && flagSet("seed") {
+		die("-seed-from-string and -seed are mutually exclusive")
+	}
+	if err := setupLogging(); err != nil {
+		die("%v", err)
+	}
+	if *configPath != "" {
+		if err := loadConfig(*configPath); err != nil {
+			die("load config failed, %v", err)
+		}
+	}
+	if *numColors < 2 || *numColors > len(colors) {
+		die("-num-colors must be between 2 and %d, got %d", len(colors), *numColors)
+	}
+	if *antiRepeatDecay < 0 || *antiRepeatDecay > 1 {
+		die("-anti-repeat-decay must be between 0 and 1, got %v", *antiRepeatDecay)
+	}
+	if *finiteStock {
+		if err := initStock(); err != nil {
+			die("-stock is invalid, %v", err)
+		}
+	}
+	if *targetsSpec != "" {
+		if err := initTargets(); err != nil {
+			die("-targets is invalid, %v", err)
+		}
+	} else if *stopAtTargets {
+		die("-stop-at-targets needs -targets")
+	}
+	if *weightsSpec != "" {
+		if err := initWeights(); err != nil {
+			die("-weights is invalid, %v", err)
+		}
+	}
+	if !validAmount(*toyValue) {
+		die("-toy-value must be a number that is not negative, got %v", *toyValue)
+	}
+	if *packageCost != "" {
+		if err := initPackageCosts(); err != nil {
+			die("-package-cost is invalid, %v", err)
+		}
+	}
+	if *luckyBoost <= 0 {
+		die("-lucky-boost must be positive, got %v", *luckyBoost)
+	}
+	if *branches < 0 || *branchTurn < 1 {
+		die("-branch must not be negative and -branch-turn must be at least 1, got %d and %d", *branches, *branchTurn)
+	}
+	if *stickySpec != "" {
+		if *gravity {
+			die("-sticky cannot be combined with -gravity")
+		}
+		if err := parseStickySlots(*stickySpec); err != nil {
+			die("-sticky is invalid, %v", err)
+		}
+	}
+	if *luckyColorSpec != "" && !validLuckyColorSpec() {
+		die("-color must be a color in play, or fill-gap with -collection-in, got %q", *luckyColorSpec)
+	}
+	if *holdSeconds < 0 {
+		die("-hold-seconds must not be negative, got %d", *holdSeconds)
+	}
+	if *maxToys < 0 {
+		die("-max-toys must not be negative, got %d", *maxToys)
+	}
+	if *autoContinueAfter < 0 {
+		die("-auto-continue-after must not be negative, got %d", *autoContinueAfter)
+	}
+	if *maxEventsPerTurn < 0 {
+		die("-max-events-per-turn must not be negative, got %d", *maxEventsPerTurn)
+	}
+	if *sortAcquired != "color" && *sortAcquired != "count" {
+		die("-sort-acquired must be color or count, got %q", *sortAcquired)
+	}
+	if *summaryOnlyJSON {
+		*format = "json"
+	}
+	if *format == "json" {
+		ui = os.Stderr
+	}
+	if *seedPhrase != "" {
+		*seed = seedFromString(*seedPhrase)
+	}
+	if *demo && !flagSet("seed") && *seedPhrase == "" {
+		*seed = demoSeed
+	}
+	if *seed == 0 {
+		*seed = defaultSeed()
+	}
+	rng = newRNG(*seed)
+	if *shuffleSlots {
+		if *gravity {
+			die("-shuffle-slots cannot be combined with -gravity, which fills the board from the bottom up")
+		}
+		slotOrder = shuffledSlotOrder()
+		if *verbose {
+			_, _ = fmt.Fprintf(ui, "Slot order: %v\n", slotOrder)
+		}
+	}
+	r := newRenderer(os.Stdout)
+	if *scriptPath != "" {
+		s, err := loadScript(*scriptPath)
+		if err != nil {
+			die("load script failed, %v", err)
+		}
+		script = s
+	}
+	if *luckyCenter {
+		registerLuckyCenter()
+	}
+	if *wildChance < 0 || *wildChance > 1 {
+		die("-wild-chance must be between 0 and 1, got %v", *wildChance)
+	} else if *wildChance > 0 {
+		registerWildDraw()
+	}
+	if *toroidal || *noDiagonals {
+		tripleCombination = linePlacements(boardSize, *toroidal, !*noDiagonals)
+		shapes[0].placements = tripleCombination
+	}
+	if *cornersPoints < 0 {
+		die("-corners must not be negative, got %d", *cornersPoints)
+	} else if *cornersPoints > 0 {
+		registerCorners()
+	}
+	if *nearClearSpec != "" {
+		if err := initNearClear(); err != nil {
+			die("-near-clear is invalid, %v", err)
+		}
+	}
+	if *shapesFile != "" {
+		if err := loadShapes(*shapesFile); err != nil {
+			die("load shapes failed, %v", err)
+		}
+	}
+	if *boardFile != "" {
+		if err := loadBoardFile(*boardFile); err != nil {
+			die("load board failed, %v", err)
+		}
+	}
+	if *eventsFile != "" {
+		if err := loadCustomEvents(*eventsFile); err != nil {
+			die("load events failed, %v", err)
+		}
+	}
+	if *eventPointsChanceSpec != "" {
+		if err := parseEventPointsChances(*eventPointsChanceSpec); err != nil {
+			die("-event-points-chance is invalid, %v", err)
+		}
+	}
+	if *messagesFile != "" {
+		if err := loadMessages(*messagesFile); err != nil {
+			die("load messages failed, %v", err)
+		}
+	}
+	configureEvents()
+	if *multiplierCards != "" {
+		deck, err := parseMultiplierCards(*multiplierCards)
+		if err != nil {
+			die("-multiplier-cards is invalid, %v", err)
+		}
+		multiplierDeck = deck
+	}
+	if *dumpConfig != "" {
+		if err := saveConfig(*dumpConfig); err != nil {
+			die("dump config failed, %v", err)
+		}
+	}
+	if *helpRules {
+		printRules(os.Stdout)
+		return
+	}
+	if *benchmarkConfig {
+		runBenchmark(os.Stdout)
+		return
+	}
+	if *split != 0 {
+		if *split < 2 || *split > *numColors {
+			die("-split must be between 2 and %d, got %d", *numColors, *split)
+		}
+		if *format != "text" || *demo {
+			die("-split only supports the interactive text output")
+		}
+		playSplit()
+	} else {
+		interactive(r)
+	}
+	waitWebhooks()
+	holdSummary()
+}
+
+// holdSummary function waits for -hold-seconds once the final summary is printed, unless -quiet is set.
+func holdSummary() {
+	if *holdSeconds > 0 && !*quiet {
+		sleep(time.Duration(*holdSeconds) * time.Second)
+	}
+}
+
+// flagSet function reports whether the named flag was set on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// configureEvents function applies the event toggles to disabledEvents and eventRewardRules.
+// A disabled event awards no points and is not reported. The Family Portrait still empties the full board when disabled,
+// and its toys are still collected, as the game could not go on otherwise.
+// With -reallocate-to, the given event receives the points of a disabled event on each turn the disabled event fires,
+// see reallocatedPoints, so that the points and the toys of a game are those of the game without the toggles.
+func configureEvents() {
+	if *noClearEvent {
+		disabledEvents[eventClear] = true
+	}
+	if *noFamilyEvent {
+		disabledEvents[eventAllDifferent] = true
+	}
+	target := -1
+	if *reallocateTo != "" {
+		target = slices.Index(eventNames, *reallocateTo)
+		if target != eventOnePair && target != eventLuckyStrike {
+			die("-reallocate-to must be one-pair or lucky-strike, got %q", *reallocateTo)
+		}
+	}
+	for e := range disabledEvents {
+		if target >= 0 {
+			reallocatedPoints[e] = eventRewardRules[e]
+		}
+		eventRewardRules[e] = 0
+	}
+}
+
+// applyPackageRewards function sets the Lucky Color reward for the selected package when -lucky-scale is set.
+// It runs before the first turn, so that placeInSlot, handleEvents and the renderers all agree on the reward.
+func applyPackageRewards(packageSize int) {
+	if !*luckyScale {
+		return
+	}
+	scale, ok := luckyColorScale[packageSize]
+	if !ok {
+		return
+	}
+	eventAcquired[eventLuckyColor] = scale.acquired
+	eventRewardRules[eventLuckyColor] = scale.points
+}
+
+// paletteWarnings function returns a description of the events that the configured palette makes impossible.
+// The game still runs; the warnings only tell the player what to expect.
+func paletteWarnings() []string {
+	warnings := make([]string, 0)
+	if slots := len(initialOrderedSlots); *numColors < slots && !disabledEvents[eventAllDifferent] {
+		warnings = append(warnings, fmt.Sprintf("only %d colors are in play, %s needs %d distinct colors on the board and can never fire",
+			*numColors, eventDesc[eventAllDifferent], slots))
+	}
+	return warnings
+}
+
+// printPaletteWarnings function prints the paletteWarnings before the first game starts.
+func printPaletteWarnings() {
+	for _, w := range paletteWarnings() {
+		_, _ = fmt.Fprintf(ui, "Warning: %s\n", w)
+	}
+}
+
+// logPanic function, deferred while a game is played, logs the turn returned by turn and the seed of a panic so that
+// the game can be reproduced, then lets the panic go on.
+func logPanic(turn func() int) {
+	if p := recover(); p != nil {
+		slog.Error("panic during the game, rerun with the seed to reproduce", "turn", turn(), "seed", *seed)
+		panic(p)
+	}
+}
+
+// reportedEvents function returns the events that are not disabled.
+func reportedEvents(events []ev) []ev {
+	reported := make([]ev, 0, len(events))
+	for _, e := range events {
+		if !disabledEvents[e.event] {
+			reported = append(reported, e)
+		}
+	}
+	return reported
 }
 
 // interactive function runs the main loop of the game, guiding the user through the entire gameplay process.
 // It starts the game, selects the lucky color, selects the toy package, and then continuously places toys on the board,
 // checks for events, and handles acquired items. The loop continues until all the remaining toys are placed.
-func interactive() {
-	startGame()
-	luckColor := selectLuckColor()
-	remaining := selectPackageType()
-	board := make([]int, 9)
-	acquired := make([]int, len(colors))
-	orderedEmptySlots := initialOrderedSlots
-	for remaining > 0 {
-		events := make([]ev, 0)
-		remaining, events, orderedEmptySlots = placeInSlot(board, orderedEmptySlots, events, remaining, luckColor)
-		printBoard(board)
-		events, orderedEmptySlots = checkBoard(board, orderedEmptySlots, events)
-		printEvents(events)
-		remaining = handleEvents(events, acquired, remaining)
-		printAcquired(acquired, false)
-		fmt.Printf("Remaining: %d\n", remaining)
-		next()
-	}
-	for _, v := range board {
-		if v > 0 {
-			acquired[v-1] += 1
+// The progress and the outcome of the game are rendered by r.
+func interactive(r Renderer) {
+	printPaletteWarnings()
+	collection := make([]int, len(colors))
+	if *collectionIn != "" {
+		var err error
+		if collection, err = loadCollection(*collectionIn); err != nil {
+			die("load collection failed, %v", err)
+		}
+	}
+	var luckColor, packageSize int
+	if *demo {
+		luckColor, packageSize = startDemo()
+	} else {
+		startGame()
+		if *luckyColorSpec != "" {
+			luckColor = pickLuckyColor(collection)
+		} else {
+			suggested := 0
+			if *collectionIn != "" {
+				suggested = recommendLuckyColor(collection)
+				_, _ = fmt.Fprintf(ui, "Suggestion: your collection is shortest on %s, consider making it your lucky color\n", colors[suggested-1])
+			}
+			luckColor = selectLuckColor(suggested)
+		}
+		packageSize = selectPackageType()
+	}
+	if err := checkPackageCost(packageSize); err != nil {
+		die("-package-cost is invalid, %v", err)
+	}
+	applyPackageRewards(packageSize)
+	applyMultiplierCard()
+	if *odds {
+		printOdds(ui, luckColor)
+	}
+	g := newGame(luckColor, packageSize)
+	g.boostStarter(starterSlots(g.board))
+	if *pauseOnReset && !*demo {
+		g.confirmReset = confirmReset
+	}
+	if *verbose {
+		_, _ = fmt.Fprintf(ui, "Seed: %d\n", *seed)
+	}
+	fp := fingerprint()
+	_, _ = fmt.Fprintf(ui, "Fingerprint: %s\n", fp)
+	defer logPanic(func() int { return g.turn })
+	slog.Info("game start", "seed", *seed, "lucky_color", colors[luckColor-1], "package", packageSize)
+	thinkTimes, dry := make([]time.Duration, 0), 0
+	for !g.over() {
+		reported := g.playTurn(r)
+		if *branches > 0 && g.turn == *branchTurn {
+			printBranches(ui, g.turn, g.branchOutcomes(*branches))
+		}
+		if *demo {
+			if _, ok := r.(*DashboardRenderer); ok {
+				// The status line already names the events of the turn, a narration would break it.
+				reported = nil
+			}
+			narrateDemo(reported)
+			continue
+		}
+		if dry = dry + 1; len(reported) > 0 {
+			dry = 0
+		}
+		if *autoContinueAfter > 0 && dry >= *autoContinueAfter {
+			continue
+		}
+		proceed, think := timed(next)
+		g.cancelled = !proceed
+		thinkTimes = append(thinkTimes, think)
+	}
+	res := g.finish()
+	res.Fingerprint = fp
+	slog.Info("game end", "seed", *seed, "turn", g.turn, "total", res.Total, "score", res.Score, "reason", res.TerminationReason)
+	if *verbose {
+		_, _ = fmt.Fprintf(ui, "RNG calls: %d\n", res.RNGCalls)
+	}
+	if *collectionOut != "" {
+		if err := saveCollection(*collectionOut, mergeCollection(collection, g.acquired)); err != nil {
+			die("save collection failed, %v", err)
+		}
+	}
+	r.Summary(res)
+	if *timeTurns && script == nil {
+		printThinkTimes(ui, thinkTimes)
+	}
+	if *share {
+		_, _ = fmt.Fprint(ui, shareText(res, g.board))
+	}
+	if *history {
+		printHistory(ui, g.stats.turns)
+	}
+	if *historyCSV != "" {
+		if err := writeHistoryCSV(*historyCSV, g.stats.turns); err != nil {
+			die("write history failed, %v", err)
+		}
+	}
+	if *timelineJSON != "" {
+		if err := writeTimeline(*timelineJSON, g.stats.turns, res); err != nil {
+			die("write timeline failed, %v", err)
 		}
 	}
-	printAcquired(acquired, true)
 }
 
-// placeInSlot function randomly places colors into empty slots on the board
-// and generates events for lucky color occurrences during the process.
-func placeInSlot(board, orderedEmptySlots []int, events []ev, remaining, luckyColor int) (int, []ev, []int) {
+// gameResult is the final outcome of a game, as rendered by Renderer.Summary.
+type gameResult struct {
+	LuckyColor string         `json:"lucky_color"`
+	Package    int            `json:"package"`
+	Acquired   map[string]int `json:"acquired"`
+	Total      int            `json:"total"`
+	Score      int            `json:"score"`
+
+	TerminationReason terminationReason `json:"termination_reason"`
+
+	Rarity             float64 `json:"rarity"`
+	Efficiency         float64 `json:"efficiency"`
+	PeakDistinctColors int     `json:"peak_distinct_colors"`
+	LongestDryStreak   int     `json:"longest_dry_streak"`
+	MaxPairsPerTurn    int     `json:"max_pairs_per_turn"`
+	OverlappingSlots   int     `json:"overlapping_slots"`
+	MaxOverlapsPerTurn int     `json:"max_overlaps_per_turn"`
+
+	// Closest holds how close the game came to the rare events that never fired, keyed by event name, see
+	// gameStats.closestApproach.
+	Closest map[string]int `json:"closest,omitempty"`
+
+	RNGCalls    uint64 `json:"rng_calls"`
+	Fingerprint string `json:"fingerprint"`
+
+	Spend *spendReport `json:"spend,omitempty"`
+}
+
+// newGameResult function builds the final outcome of a game from the lucky color, the selected package,
+// the acquired toys and the metrics collected over the game.
+func newGameResult(luckyColor, packageSize int, acq []int, stats gameStats) gameResult {
+	res := gameResult{
+		LuckyColor:         colors[luckyColor-1],
+		Package:            packageSize,
+		Acquired:           colorTally(acq),
+		PeakDistinctColors: stats.peakDistinct,
+		LongestDryStreak:   stats.longestDryStreak,
+		MaxPairsPerTurn:    stats.maxPairs,
+		OverlappingSlots:   stats.overlaps,
+		MaxOverlapsPerTurn: stats.maxOverlaps,
+		Closest:            stats.closestApproach(),
+		Efficiency:         stats.efficiency(),
+		RNGCalls:           rngSource.calls,
+	}
+	for _, v := range acq {
+		res.Total += v
+	}
+	spend, err := newSpendReport(packageSize, res.Total)
+	if err != nil {
+		die("spend report failed, %v", err)
+	}
+	res.Spend = spend
+	return res
+}
+
+// placeInSlot function randomly places colors, drawn with draw, into empty slots on the board
+// and generates events for lucky color occurrences during the process. It stops early when draw returns 0.
+// Under -lucky-center a toy of the lucky color goes to the center slot while it is empty, see luckyCenter.
+// Under -wild-chance a wild toy can follow each toy placed, rolled with roll, see wildDraw.
+func placeInSlot(board, orderedEmptySlots []int, events []ev, remaining, luckyColor int, draw func() int, roll func() float64) (int, []ev, []int) {
+	centered := false
 	for len(orderedEmptySlots) > 0 {
 		if remaining <= 0 {
 			break
 		}
+		randColor := draw()
+		if randColor == 0 {
+			break
+		}
 		remaining -= 1
-		randColor := rand.IntN(cap(board)) + 1
 		if randColor == luckyColor {
 			events = append(events, ev{map[int]int{randColor: eventAcquired[eventLuckyColor]}, eventLuckyColor})
+			if *luckyCenter && !centered {
+				orderedEmptySlots, centered = takeCenterSlot(orderedEmptySlots)
+			}
 		}
 		board[orderedEmptySlots[0]] = randColor
 		orderedEmptySlots = orderedEmptySlots[1:]
+		events, orderedEmptySlots, remaining = wildDraw(board, orderedEmptySlots, events, remaining, draw, roll)
+	}
+	if centered && centerLineComplete(board) {
+		events = append(events, ev{map[int]int{luckyColor: 0}, eventLuckyCenter})
 	}
 	return remaining, events, orderedEmptySlots
 }
 
 // checkBoard function checks the current state of the board for specific combinations and updates the board, empty slots, and events accordingly.
-func checkBoard(board, orderedEmptySlots []int, events []ev) ([]ev, []int) {
-	for _, comb := range tripleCombination {
-		if board[comb[0]] != 0 && board[comb[0]] == board[comb[1]] && board[comb[0]] == board[comb[2]] {
-			events = append(events, ev{map[int]int{board[comb[0]]: eventAcquired[eventLuckyStrike]}, eventLuckyStrike})
-			orderedEmptySlots = append(orderedEmptySlots, comb...)
-			board[comb[0]] = 0
-			board[comb[1]] = 0
-			board[comb[2]] = 0
+// The custom events of -events-file are checked first, see detectCustomEvents.
+// Completed lines and custom shapes (see shapesFile) are all detected against the board as it was before any slot is
+// cleared, and are resolved in ascending order of their lowest slot (ties broken by the following slots). Two lines
+// completed at once, e.g. a row and a column sharing the freshly placed slot, are therefore both awarded, while the
+// shared slot is cleared and returned to the empty slots only once. The sticky slots of -sticky are not cleared by a
+// match, unless all its toys are in sticky slots, see stickySpec.
+//
+// Toys of the same color that are not in a line are paired in slot order. By default every such color is paired as
+// many times as possible, e.g. four toys of a color make two pairs and three toys make one pair and a leftover that
+// waits for a future toy of its color. With -pair-greedy=false at most one pair per color is resolved per call, and
+// further pairs of that color are deferred like the matches beyond -max-events-per-turn below.
+//
+// With -max-events-per-turn, at most that many lines, shapes and pairs (lines and shapes first, in the order above) are resolved per call.
+// The remaining matches are deferred: they are left on the board untouched and are detected again by the next call,
+// after the next toys were placed, provided they are still complete. Neither Clear The Board nor Family Portrait can
+// fire while a match is deferred. Matches still deferred when the game ends are collected with the leftover toys,
+// without their events.
+//
+// On a Family Portrait the board is left as is and checkBoard reports that it must be reset: the caller empties it
+// with resetBoard, e.g. after the player confirmed under -pause-on-reset.
+func checkBoard(board, orderedEmptySlots []int, events []ev) ([]ev, []int, bool) {
+	events = detectCustomEvents(board, events)
+	completed := make([]match, 0)
+	for _, sh := range shapes {
+		for _, slots := range sh.placements {
+			if filled(board, slots) {
+				completed = append(completed, match{sh.event, sortedSlots(slots)})
+			}
+		}
+	}
+	slices.SortStableFunc(completed, func(a, b match) int { return slices.Compare(a.slots, b.slots) })
+	budget, deferred := *maxEventsPerTurn, false
+	if budget > 0 && len(completed) > budget {
+		completed, deferred = completed[:budget], true
+	}
+	budget -= len(completed)
+	for _, m := range completed {
+		events = append(events, ev{map[int]int{board[m.slots[0]]: eventAcquired[m.event]}, m.event})
+	}
+	for _, m := range completed {
+		sticks := !allSticky(m.slots)
+		for _, slot := range m.slots {
+			if board[slot] != 0 && !(sticks && stickySlots[slot]) {
+				board[slot] = 0
+				orderedEmptySlots = append(orderedEmptySlots, slot)
+			}
 		}
 	}
 	rt := make(map[int]int)
+	paired := make(map[int]bool)
 	for k, v := range board {
 		if v > 0 {
 			if pos, ok := rt[v]; ok {
+				if (*maxEventsPerTurn > 0 && budget <= 0) || (!*pairGreedy && paired[v]) {
+					deferred = true
+					continue
+				}
+				budget--
 				events = append(events, ev{map[int]int{board[k]: eventAcquired[eventOnePair]}, eventOnePair})
-				board[pos] = 0
-				board[k] = 0
-				orderedEmptySlots = append(orderedEmptySlots, pos, k)
+				sticks := !allSticky([]int{pos, k})
+				for _, slot := range []int{pos, k} {
+					if !(sticks && stickySlots[slot]) {
+						board[slot] = 0
+						orderedEmptySlots = append(orderedEmptySlots, slot)
+					}
+				}
 				delete(rt, v)
+				paired[v] = true
 			} else {
 				rt[v] = k
 			}
@@ -164,27 +778,135 @@ func checkBoard(board, orderedEmptySlots []int, events []ev) ([]ev, []int) {
 	if len(orderedEmptySlots) == cap(board) {
 		events = append(events, ev{map[int]int{}, eventClear})
 	}
-	if len(orderedEmptySlots) == 0 {
+	reset := false
+	if len(orderedEmptySlots) == 0 && !deferred && distinctColors(board) == len(board) {
 		acq := map[int]int{}
 		for _, v := range board {
 			acq[v] = 1
 		}
-		board = make([]int, 9)
-		orderedEmptySlots = initialOrderedSlots
 		events = append(events, ev{acq, eventAllDifferent})
+		reset = true
 	}
-	sort.Slice(orderedEmptySlots, func(i, j int) bool {
-		return orderedEmptySlots[i] < orderedEmptySlots[j]
+	if *gravity {
+		applyGravity(board)
+	}
+	return events, emptySlots(board), reset
+}
+
+// match is a line or a custom shape completed on the board, with its slots in ascending order.
+type match struct {
+	event int
+	slots []int
+}
+
+// filled function reports whether the slots hold toys of a single color.
+func filled(board, slots []int) bool {
+	for _, k := range slots {
+		if board[k] == 0 || board[k] != board[slots[0]] {
+			return false
+		}
+	}
+	return true
+}
+
+// stackedLuckyToys function returns the number of toys of the lucky color (1-based) placed in the placed slots that lie
+// in a completed line of the board.
+func stackedLuckyToys(board, placed []int, luckyColor int) int {
+	n := 0
+	for _, k := range placed {
+		if board[k] != luckyColor {
+			continue
+		}
+		if slices.ContainsFunc(tripleCombination, func(line []int) bool { return slices.Contains(line, k) && filled(board, line) }) {
+			n++
+		}
+	}
+	return n
+}
+
+// unstackEvents function keeps, between the Lucky Color events of the stacked toys of the lucky color (1-based) that
+// completed a line and the Lucky Strike of that color, only the events with the higher reward, see noEventStacking.
+// The toys of a dropped Lucky Strike are still acquired in acq, indexed by color (0-based). Nothing is dropped when no
+// Lucky Strike of the lucky color fired, e.g. when the line was deferred by -max-events-per-turn.
+func unstackEvents(events []ev, stacked, luckyColor int, acq []int) []ev {
+	isStrike := func(e ev) bool {
+		_, ok := e.acquired[luckyColor]
+		return e.event == eventLuckyStrike && ok
+	}
+	if stacked == 0 || !slices.ContainsFunc(events, isStrike) {
+		return events
+	}
+	if eventRewardRules[eventLuckyColor] > eventRewardRules[eventLuckyStrike] {
+		return slices.DeleteFunc(events, func(e ev) bool {
+			if !isStrike(e) {
+				return false
+			}
+			for k, v := range e.acquired {
+				acq[k-1] += v
+			}
+			return true
+		})
+	}
+	return slices.DeleteFunc(events, func(e ev) bool {
+		if e.event != eventLuckyColor || stacked == 0 {
+			return false
+		}
+		stacked--
+		return true
+	})
+}
+
+// resetBoard function empties the board after a Family Portrait and returns its empty slots.
+func resetBoard(board []int) []int {
+	clear(board)
+	return emptySlots(board)
+}
+
+// applyGravity function lets the toys of each column fall to the bottom of the board, keeping their order.
+func applyGravity(board []int) {
+	for col := 0; col < boardSize; col++ {
+		dst := len(board) - boardSize + col
+		for src := dst; src >= 0; src -= boardSize {
+			if board[src] != 0 {
+				board[dst], board[src] = board[src], board[dst]
+				dst -= boardSize
+			}
+		}
+	}
+}
+
+// shuffledSlotOrder function returns the slots of initialOrderedSlots shuffled with rng, for -shuffle-slots.
+func shuffledSlotOrder() []int {
+	order := slices.Clone(initialOrderedSlots)
+	rng.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
 	})
-	return events, orderedEmptySlots
+	return order
+}
+
+// emptySlots function returns the empty slots of the board in the order they are filled by placeInSlot.
+// Slots are filled in the order of slotOrder, except under -gravity where the bottom row is filled first: a toy dropped
+// from the top falls onto the toys already in its column.
+func emptySlots(board []int) []int {
+	slots := make([]int, 0, len(board))
+	for _, k := range slotOrder {
+		if *gravity {
+			k = (boardSize-1-k/boardSize)*boardSize + k%boardSize
+		}
+		if board[k] == 0 {
+			slots = append(slots, k)
+		}
+	}
+	return slots
 }
 
 // handleEvents function processes a list of events and updates the acquired rewards for each event.
 // It calculates the total reward based on the event rules and updates the acquired rewards for specific items.
+// A disabled event awards its points to the event of -reallocate-to instead, see reallocatedPoints.
 func handleEvents(events []ev, acq []int, remaining int) int {
 	n := 0
 	for _, e := range events {
-		n += eventRewardRules[e.event]
+		n += eventRewardRules[e.event] + reallocatedPoints[e.event]
 		for k, v := range e.acquired {
 			acq[k-1] += v
 		}
@@ -192,69 +914,110 @@ func handleEvents(events []ev, acq []int, remaining int) int {
 	return n + remaining
 }
 
-// printEvents function prints the details of each event in the provided events list.
-// It displays the event description and the associated reward for each event.
-func printEvents(events []ev) {
-	if len(events) != 0 {
-		fmt.Println("========== events ==========")
+// next function prompts the user to press "Enter" to continue the game.
+// It displays a prompt with the label "Please type enter to continue game" and waits for the user to press the Enter key.
+// Typing "h" instead prints the rules and the current configuration, then prompts again.
+// It returns false when the user interrupts the prompt (Ctrl+C) to cancel the game.
+func next() bool {
+	if script != nil {
+		return script.proceed()
 	}
-	for _, e := range events {
-		fmt.Printf("Event: %-20s +%d\n", eventDesc[e.event], eventRewardRules[e.event])
+	prompt := promptui.Prompt{
+		Label:  "Please type enter to continue game (h for help)",
+		Stdout: ui,
+	}
+	for {
+		input, err := prompt.Run()
+		if errors.Is(err, promptui.ErrInterrupt) {
+			return false
+		}
+		if strings.TrimSpace(input) != "h" {
+			return true
+		}
+		printRules(ui)
 	}
 }
 
-// printAcquired function prints the list of acquired items (e.g., toys) along with their quantities.
-// If the `finish` flag is set to true, it also prints the total number of acquired items.
-func printAcquired(acq []int, finish bool) {
-	fmt.Println("========== acquired ==========")
-	n := 0
-	for k, v := range acq {
-		fmt.Printf("%s: %d; ", colors[k], v)
-		n += v
+// confirmReset function explains the Family Portrait about to reset the board and waits for the user to press "Enter".
+// It returns false when the user interrupts the prompt (Ctrl+C) to cancel the game.
+func confirmReset() bool {
+	_, _ = fmt.Fprintf(ui, "Family Portrait: every slot holds a different color, so you acquire one toy of each "+
+		"and the board is emptied (+%d points)\n", eventRewardRules[eventAllDifferent])
+	if script != nil {
+		return script.proceed()
 	}
-	if finish {
-		fmt.Printf("\nYou have received %d toys\n", n)
+	prompt := promptui.Prompt{
+		Label:  "Please type enter to reset the board",
+		Stdout: ui,
 	}
+	_, err := prompt.Run()
+	return !errors.Is(err, promptui.ErrInterrupt)
 }
 
-// printBoard function prints the current state of the board, showing the items (e.g., colors) placed in each slot.
-// If a slot is empty, it prints "Empty" for that slot. The board is printed in a grid format, with 3 items per row.
-func printBoard(board []int) {
-	fmt.Println("========== board ==========")
-	for i, v := range board {
-		if v <= 0 {
-			fmt.Printf("%-10s ", "Empty")
-		} else {
-			fmt.Printf("%-10s ", colors[v-1])
+// introduction function returns the introduction of the game, listing the reward of each event as currently configured.
+func introduction() string {
+	var b strings.Builder
+	b.WriteString("Game Introduction")
+	for e, desc := range eventDesc {
+		if points, ok := reallocatedPoints[e]; ok {
+			_, _ = fmt.Fprintf(&b, "\n%d. %s (disabled, its +%d go to %s)", e+1, desc, points, eventDesc[slices.Index(eventNames, *reallocateTo)])
+			continue
 		}
-		if i%3 == 2 {
-			fmt.Print("\n")
+		if disabledEvents[e] {
+			_, _ = fmt.Fprintf(&b, "\n%d. %s (disabled)", e+1, desc)
+			continue
 		}
+		_, _ = fmt.Fprintf(&b, "\n%d. %s +%d", e+1, desc, eventRewardRules[e])
 	}
+	return b.String()
 }
 
-// next function prompts the user to press "Enter" to continue the game.
-// It displays a prompt with the label "Please type enter to continue game" and waits for the user to press the Enter key.
-func next() {
-	prompt := promptui.Prompt{
-		Label: "Please type enter to continue game",
+// printRules function prints the full rules of the game, the reward of each event and the current configuration.
+func printRules(w io.Writer) {
+	_, _ = fmt.Fprintln(w, introduction())
+	_, _ = fmt.Fprintln(w, `Rules
+Toys are drawn at random and placed in the empty slots of the 3x3 board, in slot order.
+Each event awards points, and every point is one more toy to place.
+Lucky Color: a toy of the lucky color is drawn.
+One Pair: two toys of the same color are on the board; both are acquired.
+Lucky Strike: three toys of the same color lie in `+lineDescription()+`; all three are acquired.
+Family Portrait: the board is full and every toy has a distinct color; all of them are acquired.
+Clear The Board: no toy is left on the board after the matches.
+When no toy remains to be placed, the toys left on the board are acquired too.`)
+	_, _ = fmt.Fprintln(w, "Configuration")
+	_, _ = fmt.Fprintf(w, "Colors in play: %s\n", strings.Join(colors[:*numColors], ", "))
+	_, _ = fmt.Fprintf(w, "Seed: %d\n", *seed)
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name != "seed" && f.Name != "help-rules" {
+			_, _ = fmt.Fprintf(w, "-%s=%s\n", f.Name, f.Value)
+		}
+	})
+}
+
+// lineDescription function describes the lines of the Lucky Strike under -no-diagonals and -toroidal, e.g.
+// "a row, a column or a diagonal".
+func lineDescription() string {
+	lines := "a row, a column or a diagonal"
+	if *noDiagonals {
+		lines = "a row or a column"
 	}
-	_, _ = prompt.Run()
+	if *toroidal {
+		lines += ", wrapping around the edges of the board"
+	}
+	return lines
 }
 
 // startGame function displays a brief introduction to the game, listing the rewards for various events,
 // and then prompts the user to press "Enter" to start the game.
 // It provides an overview of the game rules and waits for the user to continue before starting the game.
 func startGame() {
-	description := `Game Introduction
-1. Lucky Color +1
-2. One Pair +1
-3. Lucky Strike +3
-4. Family Portrait +5
-5. Clear The Board +5`
-	fmt.Println(description)
+	_, _ = fmt.Fprintln(ui, introduction())
+	if script != nil {
+		return
+	}
 	prompt := promptui.Prompt{
-		Label: "Please type enter to start game",
+		Label:  "Please type enter to start game",
+		Stdout: ui,
 	}
 	_, _ = prompt.Run()
 }
@@ -267,30 +1030,45 @@ func selectPackageType() int {
 	for _, v := range packages {
 		items = append(items, fmt.Sprintf("%d toys", v))
 	}
+	if script != nil {
+		n, _ := strconv.Atoi(script.selection("select-package"))
+		_, _ = fmt.Fprintf(ui, "You choose %d toys \n", n)
+		return n
+	}
 	prompt := promptui.Select{
-		Label: "Select your toy package",
-		Items: items,
+		Label:  "Select your toy package",
+		Items:  items,
+		Stdout: ui,
 	}
 	packIdx, _, err := prompt.Run()
 	if err != nil {
 		die("choose toy package failed, %v\n", err)
 	}
-	fmt.Printf("You choose %s \n", items[packIdx])
+	_, _ = fmt.Fprintf(ui, "You choose %s \n", items[packIdx])
 	return packages[packIdx]
 }
 
 // selectLuckColor function prompts the user to select their lucky color from a list of available colors.
 // It displays a list of colors and waits for the user to choose one. After the user makes a selection,
 // the function prints the selected color and returns the index of the chosen color (1-based).
-func selectLuckColor() int {
+// When suggested (1-based) is not 0, the list opens on that color.
+func selectLuckColor(suggested int) int {
+	if script != nil {
+		c := slices.Index(colors, script.selection("select-color"))
+		_, _ = fmt.Fprintf(ui, "You choose %s \n", colors[c])
+		return c + 1
+	}
 	prompt := promptui.Select{
-		Label: "Select your lucky color",
-		Items: colors,
+		Label:  "Select your lucky color",
+		Items:  colors[:*numColors],
+		Size:   5,
+		Stdout: ui,
 	}
-	colorIdx, _, err := prompt.Run()
+	cursor := max(suggested-1, 0)
+	colorIdx, _, err := prompt.RunCursorAt(cursor, max(cursor-prompt.Size+1, 0))
 	if err != nil {
 		die("choose lucky color failed, %v\n", err)
 	}
-	fmt.Printf("You choose %s \n", colors[colorIdx])
+	_, _ = fmt.Fprintf(ui, "You choose %s \n", colors[colorIdx])
 	return colorIdx + 1
 }
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setFlag function sets the named flag to value for the duration of the test.
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+	f := flag.Lookup(name)
+	old := f.Value.String()
+	if err := f.Value.Set(value); err != nil {
+		t.Fatalf("set -%s=%s: %v", name, value, err)
+	}
+	t.Cleanup(func() { _ = f.Value.Set(old) })
+}
+
+// isolateFlags function gives the test a command line of its own, so that flags set with flag.Set count as given on
+// the command line only for the test. The flags of the testing package are left out, and the values of every flag are
+// restored once the test ends.
+func isolateFlags(t *testing.T) {
+	t.Helper()
+	old := flag.CommandLine
+	values := make(map[string]string)
+	fs := flag.NewFlagSet(old.Name(), flag.ContinueOnError)
+	old.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.String()
+		if !strings.HasPrefix(f.Name, "test.") {
+			fs.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	flag.CommandLine = fs
+	t.Cleanup(func() {
+		flag.CommandLine = old
+		for name, v := range values {
+			_ = old.Lookup(name).Value.Set(v)
+		}
+	})
+}
+
+// useSeed function seeds the generator of the game with s for the duration of the test.
+func useSeed(t *testing.T, s uint64) {
+	t.Helper()
+	oldSeed, oldRNG, oldSource, oldLastDrawn := *seed, rng, rngSource, lastDrawn
+	*seed, rng, lastDrawn = s, newRNG(s), 0
+	t.Cleanup(func() { *seed, rng, rngSource, lastDrawn = oldSeed, oldRNG, oldSource, oldLastDrawn })
+}
+
+// nopCloser wraps a writer into the io.WriteCloser of ui.
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+// captureUI function redirects ui to a buffer for the duration of the test and returns it.
+func captureUI(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	old := ui
+	ui = nopCloser{&buf}
+	t.Cleanup(func() { ui = old })
+	return &buf
+}
+
+// useClock function replaces the clock now with clock for the duration of the test.
+func useClock(t *testing.T, clock func() time.Time) {
+	t.Helper()
+	old := now
+	now = clock
+	t.Cleanup(func() { now = old })
+}
+
+// saveEvents function restores the events, their rewards, the lines and the shapes once the test ends.
+func saveEvents(t *testing.T) {
+	desc, names, explanations := slices.Clone(eventDesc), slices.Clone(eventNames), slices.Clone(eventExplanations)
+	rewards, acquired := maps.Clone(eventRewardRules), maps.Clone(eventAcquired)
+	disabled, reallocated := maps.Clone(disabledEvents), maps.Clone(reallocatedPoints)
+	messages, chances := maps.Clone(eventMessages), maps.Clone(eventPointsChances)
+	lines, patterns, custom, tiers := tripleCombination, slices.Clone(shapes), customEvents, nearClearTiers
+	center, wild := eventLuckyCenter, eventWildDraw
+	t.Cleanup(func() {
+		eventDesc, eventNames, eventExplanations = desc, names, explanations
+		eventRewardRules, eventAcquired = rewards, acquired
+		disabledEvents, reallocatedPoints = disabled, reallocated
+		eventMessages, eventPointsChances = messages, chances
+		tripleCombination, shapes, customEvents, nearClearTiers = lines, patterns, custom, tiers
+		eventLuckyCenter, eventWildDraw = center, wild
+	})
+}
+
+// playGame function plays g to its end, rendering it with r, and returns its outcome.
+func playGame(g *game, r Renderer) gameResult {
+	for !g.over() {
+		g.playTurn(r)
+	}
+	res := g.finish()
+	r.Summary(res)
+	return res
+}
+
+// eventTypes function returns the event type of each of events, in order.
+func eventTypes(events []ev) []int {
+	types := make([]int, len(events))
+	for k, e := range events {
+		types[k] = e.event
+	}
+	return types
+}
+
+func TestCheckBoardSimultaneousLines(t *testing.T) {
+	// Placing Red in the slot 0 completes the row 0, 1, 2 and the column 0, 3, 6 at once.
+	board := []int{0, 1, 1, 1, 0, 0, 1, 0, 0}
+	draw := func() int { return 1 }
+	_, events, empty := placeInSlot(board, emptySlots(board), make([]ev, 0), 1, 2, draw, nil)
+	events, empty, reset := checkBoard(board, empty, events)
+	if want := []int{eventLuckyStrike, eventLuckyStrike, eventClear}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want %v", eventTypes(events), want)
+	}
+	for _, e := range events[:2] {
+		if e.acquired[1] != eventAcquired[eventLuckyStrike] {
+			t.Errorf("Lucky Strike acquired %v, want %d Red", e.acquired, eventAcquired[eventLuckyStrike])
+		}
+	}
+	if reset {
+		t.Error("checkBoard asked for a reset")
+	}
+	if !slices.Equal(empty, initialOrderedSlots) {
+		t.Errorf("empty slots = %v, want %v", empty, initialOrderedSlots)
+	}
+}
+
+// countEvents function returns the number of events named name in records.
+func countEvents(records []turnRecord, name string) int {
+	n := 0
+	for _, rec := range records {
+		for _, e := range rec.events {
+			if e == name {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestConfigureEventsReallocation(t *testing.T) {
+	saveEvents(t)
+	play := func() (gameResult, []turnRecord) {
+		useSeed(t, 994)
+		g := newGame(1, 30)
+		return playGame(g, discardRenderer{}), g.stats.turns
+	}
+	plain, turns := play()
+	setFlag(t, "no-clear-event", "true")
+	setFlag(t, "no-family-event", "true")
+	setFlag(t, "reallocate-to", "one-pair")
+	configureEvents()
+	if eventRewardRules[eventOnePair] != 1 {
+		t.Errorf("One Pair is worth %d after the reallocation, want its own 1 point", eventRewardRules[eventOnePair])
+	}
+	reallocated, _ := play()
+
+	clears := countEvents(turns, eventDesc[eventClear])
+	portraits := countEvents(turns, eventDesc[eventAllDifferent])
+	if clears+portraits == 0 {
+		t.Fatal("the game fired neither Clear The Board nor Family Portrait")
+	}
+	// The reallocated points still add toys, on the same turns as without the toggles, so the game plays the same.
+	if reallocated.Total != plain.Total || reallocated.Score != plain.Score {
+		t.Errorf("total and score = %d and %d with the reallocation, want %d and %d as without",
+			reallocated.Total, reallocated.Score, plain.Total, plain.Score)
+	}
+}
+
+func TestCheckBoardMaxEventsPerTurn(t *testing.T) {
+	setFlag(t, "max-events-per-turn", "2")
+	// Three pairs, each of which is an event.
+	board := []int{1, 2, 3, 1, 2, 3, 4, 5, 6}
+	events, empty, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if want := []int{eventOnePair, eventOnePair}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("first pass events = %v, want %v", eventTypes(events), want)
+	}
+	if want := []int{0, 0, 3, 0, 0, 3, 4, 5, 6}; !slices.Equal(board, want) {
+		t.Errorf("board after the first pass = %v, want the deferred pair left as is, %v", board, want)
+	}
+	events, _, _ = checkBoard(board, empty, make([]ev, 0))
+	if want := []int{eventOnePair}; !slices.Equal(eventTypes(events), want) {
+		t.Errorf("second pass events = %v, want the deferred pair, %v", eventTypes(events), want)
+	}
+}
+
+func TestApplyPackageRewardsLuckyScale(t *testing.T) {
+	saveEvents(t)
+	setFlag(t, "lucky-scale", "true")
+	rewards := make(map[int][2]int)
+	for _, size := range packages {
+		applyPackageRewards(size)
+		rewards[size] = [2]int{eventAcquired[eventLuckyColor], eventRewardRules[eventLuckyColor]}
+	}
+	if rewards[9] == rewards[30] {
+		t.Errorf("the Lucky Color reward is %v for both 9 and 30 toys, want it scaled", rewards[9])
+	}
+	for size, r := range rewards {
+		if want := luckyColorScale[size]; r != [2]int{want.acquired, want.points} {
+			t.Errorf("package of %d toys: Lucky Color acquires %d and awards %d, want %v", size, r[0], r[1], want)
+		}
+	}
+}
+
+func TestPaletteWarnings(t *testing.T) {
+	if w := paletteWarnings(); len(w) != 0 {
+		t.Errorf("warnings with the full palette = %q, want none", w)
+	}
+	setFlag(t, "num-colors", "3")
+	w := paletteWarnings()
+	if len(w) != 1 || !strings.Contains(w[0], eventDesc[eventAllDifferent]) {
+		t.Errorf("warnings with 3 colors = %q, want one about %s", w, eventDesc[eventAllDifferent])
+	}
+}
+
+func TestCheckBoardPairGreedy(t *testing.T) {
+	// Four Red toys, none of them in a line with two others.
+	layout := []int{1, 1, 2, 3, 4, 1, 1, 5, 6}
+	for _, tc := range []struct {
+		greedy string
+		want   []int
+	}{{"true", []int{eventOnePair, eventOnePair}}, {"false", []int{eventOnePair}}} {
+		setFlag(t, "pair-greedy", tc.greedy)
+		board := slices.Clone(layout)
+		events, _, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+		if !slices.Equal(eventTypes(events), tc.want) {
+			t.Errorf("-pair-greedy=%s: events = %v, want %v", tc.greedy, eventTypes(events), tc.want)
+		}
+	}
+}
+
+func TestPrintRulesEventRewards(t *testing.T) {
+	saveEvents(t)
+	eventRewardRules[eventOnePair] = 4
+	var buf bytes.Buffer
+	printRules(&buf)
+	for e, desc := range eventDesc {
+		if want := fmt.Sprintf("%s +%d", desc, eventRewardRules[e]); !strings.Contains(buf.String(), want) {
+			t.Errorf("the rules leave out %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestPrintRulesLines(t *testing.T) {
+	for _, tc := range []struct {
+		noDiagonals, toroidal string
+		want                  string
+	}{
+		{"false", "false", "lie in a row, a column or a diagonal;"},
+		{"true", "false", "lie in a row or a column;"},
+		{"true", "true", "lie in a row or a column, wrapping around the edges of the board;"},
+	} {
+		setFlag(t, "no-diagonals", tc.noDiagonals)
+		setFlag(t, "toroidal", tc.toroidal)
+		var buf bytes.Buffer
+		printRules(&buf)
+		if !strings.Contains(buf.String(), tc.want) {
+			t.Errorf("-no-diagonals=%s -toroidal=%s: the rules leave out %q:\n%s", tc.noDiagonals, tc.toroidal, tc.want, buf.String())
+		}
+	}
+}
+
+func TestCheckBoardGravity(t *testing.T) {
+	setFlag(t, "gravity", "true")
+	// The Red pair at the bottom of the board is cleared, and the column above its left toy collapses.
+	board := []int{3, 0, 0, 4, 0, 0, 1, 1, 0}
+	events, empty, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if want := []int{eventOnePair}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want %v", eventTypes(events), want)
+	}
+	if want := []int{0, 0, 0, 3, 0, 0, 4, 0, 0}; !slices.Equal(board, want) {
+		t.Errorf("board = %v, want the left column fallen to the bottom, %v", board, want)
+	}
+	if want := []int{7, 8, 4, 5, 0, 1, 2}; !slices.Equal(empty, want) {
+		t.Errorf("empty slots = %v, want the bottom row first, %v", empty, want)
+	}
+}
+
+func TestInteractiveLogsSeedOnPanic(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, 994)
+	setFlag(t, "demo", "true")
+	setFlag(t, "demo-delay", "0")
+	captureUI(t)
+	var logs bytes.Buffer
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(old) })
+	customEvents = []customEvent{{eventOnePair, func([]int) (bool, int) { panic("injected") }}}
+
+	defer func() {
+		if p := recover(); p != "injected" {
+			t.Fatalf("recovered %v, want the injected panic to propagate", p)
+		}
+		if out := logs.String(); !strings.Contains(out, "seed=994") || !strings.Contains(out, "turn=") {
+			t.Errorf("the log leaves out the seed or the turn of the panic:\n%s", out)
+		}
+	}()
+	interactive(discardRenderer{})
+}
+
+func TestShuffledSlotOrder(t *testing.T) {
+	useSeed(t, 11)
+	order := shuffledSlotOrder()
+	useSeed(t, 11)
+	if again := shuffledSlotOrder(); !slices.Equal(again, order) {
+		t.Errorf("the same seed shuffles the slots into %v and %v", order, again)
+	}
+	sorted := slices.Clone(order)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, initialOrderedSlots) {
+		t.Errorf("shuffled order %v, want each of the slots %v exactly once", order, initialOrderedSlots)
+	}
+	if slices.Equal(order, initialOrderedSlots) {
+		t.Errorf("shuffled order %v, want it to differ from the default order", order)
+	}
+}
+
+func TestGameResultRNGCalls(t *testing.T) {
+	useSeed(t, 8)
+	g := newGame(4, 30)
+	res := playGame(g, discardRenderer{})
+	placed := 0
+	for _, rec := range g.stats.turns {
+		placed += rec.placed
+	}
+	// Each uniform draw is a single rng.IntN call, and nothing else uses the generator in the default game.
+	if res.RNGCalls != uint64(placed) {
+		t.Errorf("RNG calls = %d, want one per toy placed, %d", res.RNGCalls, placed)
+	}
+}
+
+func TestStrictCountPlacements(t *testing.T) {
+	setFlag(t, "strict-count", "true")
+	for _, size := range packages {
+		for s := uint64(1); s <= 20; s++ {
+			useSeed(t, s)
+			g := newGame(1, size)
+			playGame(g, discardRenderer{})
+			placed := 0
+			for _, rec := range g.stats.turns {
+				placed += rec.placed
+			}
+			if placed != size {
+				t.Errorf("package of %d toys, seed %d: %d placements, want %d", size, s, placed, size)
+			}
+		}
+	}
+}
+
+// useStock function draws from a finite stock of spec, see -stock, for the duration of the test.
+func useStock(t *testing.T, spec string) {
+	t.Helper()
+	old := stock
+	t.Cleanup(func() { stock = old })
+	setFlag(t, "finite-stock", "true")
+	setFlag(t, "stock", spec)
+	if err := initStock(); err != nil {
+		t.Fatalf("-stock=%s: %v", spec, err)
+	}
+}
+
+func TestCheckBoardSignalsReset(t *testing.T) {
+	board := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	events, empty, reset := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if want := []int{eventAllDifferent}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want %v", eventTypes(events), want)
+	}
+	if !reset {
+		t.Error("checkBoard did not signal the reset of a full board of distinct colors")
+	}
+	if want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(board, want) || len(empty) != 0 {
+		t.Errorf("board = %v before the reset, want it left as is, %v", board, want)
+	}
+	if empty := resetBoard(board); !slices.Equal(empty, initialOrderedSlots) {
+		t.Errorf("empty slots after the reset = %v, want %v", empty, initialOrderedSlots)
+	}
+}
+
+func TestHoldSummary(t *testing.T) {
+	old := sleep
+	t.Cleanup(func() { sleep = old })
+	var slept []time.Duration
+	sleep = func(d time.Duration) { slept = append(slept, d) }
+	setFlag(t, "hold-seconds", "3")
+	holdSummary()
+	if want := []time.Duration{3 * time.Second}; !slices.Equal(slept, want) {
+		t.Errorf("slept %v, want %v", slept, want)
+	}
+	slept = nil
+	setFlag(t, "quiet", "true")
+	holdSummary()
+	if len(slept) != 0 {
+		t.Errorf("slept %v under -quiet, want no hold", slept)
+	}
+}
+
+func TestCheckBoardSecondDiagonal(t *testing.T) {
+	for _, tc := range []struct {
+		board []int
+		fires bool
+	}{
+		{[]int{2, 3, 1, 4, 1, 5, 1, 6, 7}, true},
+		{[]int{2, 3, 1, 1, 4, 5, 1, 6, 7}, false},
+	} {
+		board := slices.Clone(tc.board)
+		events, _, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+		if got := slices.Contains(eventTypes(events), eventLuckyStrike); got != tc.fires {
+			t.Errorf("board %v: Lucky Strike fired %v, want %v", tc.board, got, tc.fires)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// messagesFile loads custom display messages for the events, each declared on its own line as "Name | message":
+//
+//	# Blank lines and lines starting with # are ignored.
+//	Lucky Strike | JACKPOT!
+//	Family Portrait | Say cheese!
+//
+// The names are those of the events, built-in or custom, as displayed or as in the rewards of -dump-config, e.g.
+// lucky-strike, regardless of case. A message replaces the name of its event wherever the text output prints the
+// events, compact and dashboard lines included, and in the demo narration.
+var messagesFile = flag.String("messages", "", "load custom display messages for the events from this file")
+
+// eventMessages holds the custom display message of the events of -messages, by event type.
+var eventMessages = map[int]string{}
+
+// eventLabel function returns the display label of event e: its custom message if any, or its name.
+func eventLabel(e int) string {
+	if m, ok := eventMessages[e]; ok {
+		return m
+	}
+	return eventDesc[e]
+}
+
+// loadMessages function reads the custom display messages of the file at path.
+func loadMessages(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, message, ok := strings.Cut(text, "|")
+		name, message = strings.TrimSpace(name), strings.TrimSpace(message)
+		if !ok || message == "" {
+			return fmt.Errorf("%s:%d: want \"Name | message\", got %q", path, line, text)
+		}
+		e := slices.Index(eventNames, strings.ToLower(strings.Join(strings.Fields(name), "-")))
+		if e < 0 {
+			return fmt.Errorf("%s:%d: unknown event %q", path, line, name)
+		}
+		eventMessages[e] = message
+	}
+	return sc.Err()
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCustomMessages(t *testing.T) {
+	saveEvents(t)
+	path := filepath.Join(t.TempDir(), "messages.txt")
+	if err := os.WriteFile(path, []byte("# kiosk\nLucky Strike | JACKPOT!\nfamily-portrait | Say cheese!\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadMessages(path); err != nil {
+		t.Fatalf("load the messages: %v", err)
+	}
+	events := []ev{{map[int]int{1: 3}, eventLuckyStrike}, {map[int]int{8: 2}, eventOnePair}}
+	var text, compact, dash bytes.Buffer
+	newTextRenderer(&text, false).Events(events)
+	newTextRenderer(&compact, true).Events(events)
+	d := newDashboardRenderer(&dash)
+	d.Events(events)
+	d.Acquired(make([]int, *numColors), 0, 0)
+	for name, out := range map[string]string{"text": text.String(), "compact": compact.String(), "dashboard": dash.String()} {
+		if !strings.Contains(out, "JACKPOT!") || strings.Contains(out, "Lucky Strike") || strings.Contains(out, "LuckyStrike") {
+			t.Errorf("%s output = %q, want JACKPOT! in place of Lucky Strike", name, out)
+		}
+	}
+	if !strings.Contains(compact.String(), "OnePair(Blue)") {
+		t.Errorf("compact output = %q, want the default label of One Pair", compact.String())
+	}
+	if got := eventLabel(eventAllDifferent); got != "Say cheese!" {
+		t.Errorf("label of the Family Portrait = %q, want %q", got, "Say cheese!")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// nearClearSpec enables the Near Clear events, a tiered bonus for a turn whose matches free many slots of the board
+// without clearing it, given as comma-separated freed:points tiers, e.g. "6:1,8:2". A turn fires the event of the
+// highest tier it reaches, and none when Clear The Board fires. See initNearClear.
+var nearClearSpec = flag.String("near-clear", "", "tiered bonus for freeing many slots in a turn without clearing the board, as freed:points tiers")
+
+// nearClearTier is a tier of the Near Clear events: its event fires when a turn frees at least freed slots.
+type nearClearTier struct {
+	freed int
+	event int
+}
+
+// nearClearTiers holds the tiers of -near-clear, by decreasing number of freed slots.
+var nearClearTiers []nearClearTier
+
+// initNearClear function parses the tiers of -near-clear and registers the event of each tier next to the built-in
+// events, e.g. "Near Clear 6" for the tier of six freed slots.
+func initNearClear() error {
+	for _, field := range strings.Split(*nearClearSpec, ",") {
+		f, p, ok := strings.Cut(strings.TrimSpace(field), ":")
+		freed, errFreed := strconv.Atoi(f)
+		points, errPoints := strconv.Atoi(p)
+		if !ok || errFreed != nil || errPoints != nil {
+			return fmt.Errorf("invalid tier %q, want freed:points", field)
+		}
+		if freed < 1 || freed >= boardSize*boardSize || points < 0 {
+			return fmt.Errorf("invalid tier %q, want 1 to %d freed slots and points that are not negative", field, boardSize*boardSize-1)
+		}
+		if slices.ContainsFunc(nearClearTiers, func(t nearClearTier) bool { return t.freed == freed }) {
+			return fmt.Errorf("duplicate tier %q", field)
+		}
+		e := registerEvent(fmt.Sprintf("Near Clear %d", freed),
+			fmt.Sprintf("the matches freed at least %d slots of the board, %%d more toys to place", freed), points)
+		nearClearTiers = append(nearClearTiers, nearClearTier{freed, e})
+	}
+	slices.SortFunc(nearClearTiers, func(a, b nearClearTier) int { return b.freed - a.freed })
+	return nil
+}
+
+// occupiedSlots function returns the number of slots of the board holding a toy.
+func occupiedSlots(board []int) int {
+	n := 0
+	for _, v := range board {
+		if v > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// detectNearClear function appends the Near Clear event of the highest tier reached by a turn that freed freed slots,
+// unless Clear The Board fired.
+func detectNearClear(events []ev, freed int) []ev {
+	if slices.ContainsFunc(events, func(e ev) bool { return e.event == eventClear }) {
+		return events
+	}
+	for _, t := range nearClearTiers {
+		if freed >= t.freed {
+			return append(events, ev{map[int]int{}, t.event})
+		}
+	}
+	return events
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNearClearSixFreed(t *testing.T) {
+	saveEvents(t)
+	setFlag(t, "near-clear", "6:1,8:2")
+	nearClearTiers = nil
+	if err := initNearClear(); err != nil {
+		t.Fatal(err)
+	}
+	useSeed(t, 1)
+	g := newGame(9, 30)
+	copy(g.board, []int{1, 1, 0, 2, 2, 2, 3, 4, 5})
+	// The Red toy drawn into the slot 2 completes the top row, next to the complete middle row: six slots are freed.
+	g.orderedEmptySlots, g.remaining, g.draw = emptySlots(g.board), 1, drawSequence(1)
+	events := g.playTurn(discardRenderer{})
+	six := nearClearTiers[len(nearClearTiers)-1].event
+	if want := []int{eventLuckyStrike, eventLuckyStrike, six}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want two Lucky Strike and %s", eventTypes(events), eventDesc[six])
+	}
+	if eventRewardRules[six] != 1 {
+		t.Errorf("%s awards %d points, want 1", eventDesc[six], eventRewardRules[six])
+	}
+}
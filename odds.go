@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+)
+
+// odds prints a table of the odds of the built-in events once the lucky color and the package are selected.
+// The odds are those of the first turn, when the empty board is filled: the Lucky Color, One Pair and Family Portrait
+// odds are exact for the draw weights of the game, while the Lucky Strike odds are estimated by treating the lines as
+// independent. Clear The Board depends on the whole resolution of the turn and is not estimated.
+var odds = flag.Bool("odds", false, "print the odds of the events for the selected configuration before the game")
+
+// printOdds function prints the odds table for the lucky color luckyColor (1-based) to w.
+func printOdds(w io.Writer, luckyColor int) {
+	p := drawOdds(luckyColor)
+	slots := len(initialOrderedSlots)
+	distinct := allDistinctOdds(p, slots)
+	mono := 0.0
+	for _, q := range p {
+		mono += q * q * q
+	}
+	_, _ = fmt.Fprintf(w, "========== odds ==========\n")
+	_, _ = fmt.Fprintf(w, "%s per draw: %.2f%%\n", eventDesc[eventLuckyColor], 100*p[luckyColor-1])
+	_, _ = fmt.Fprintf(w, "Odds of the first turn, %d toys on the empty board:\n", slots)
+	_, _ = fmt.Fprintf(w, "%s: %.2f%%\n", eventDesc[eventLuckyColor], 100*(1-math.Pow(1-p[luckyColor-1], float64(slots))))
+	_, _ = fmt.Fprintf(w, "%s: %.2f%%\n", eventDesc[eventOnePair], 100*(1-distinct))
+	_, _ = fmt.Fprintf(w, "%s: %.2f%% (estimated)\n", eventDesc[eventLuckyStrike],
+		100*(1-math.Pow(1-mono, float64(len(tripleCombination)))))
+	_, _ = fmt.Fprintf(w, "%s: %.2f%%\n", eventDesc[eventAllDifferent], 100*distinct)
+}
+
+// allDistinctOdds function returns the probability that n draws with the color probabilities p are all of distinct
+// colors: n! times the elementary symmetric polynomial of degree n of p.
+func allDistinctOdds(p []float64, n int) float64 {
+	e := make([]float64, n+1)
+	e[0] = 1
+	for _, q := range p {
+		for k := n; k > 0; k-- {
+			e[k] += e[k-1] * q
+		}
+	}
+	for k := 2; k <= n; k++ {
+		e[n] *= float64(k)
+	}
+	return e[n]
+}
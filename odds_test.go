@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOddsLuckyColorPerDraw(t *testing.T) {
+	for _, n := range []int{2, 5, 9} {
+		setFlag(t, "num-colors", fmt.Sprint(n))
+		if p := drawOdds(2)[1]; p != 1/float64(n) {
+			t.Errorf("%d colors: lucky color per draw = %v, want exactly 1/%d", n, p, n)
+		}
+		var buf bytes.Buffer
+		printOdds(&buf, 2)
+		if want := fmt.Sprintf("%s per draw: %.2f%%", eventDesc[eventLuckyColor], 100/float64(n)); !strings.Contains(buf.String(), want) {
+			t.Errorf("%d colors: the odds table leaves out %q:\n%s", n, want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Renderer renders the progress and the outcome of a game.
+// Each turn, the game loop calls Board with the board right after the toys were placed in the slots listed in placed,
+// Events with the events of the
+// turn and Acquired with the toys acquired and the points scored so far; Summary is called once with the final outcome
+// of the game.
+type Renderer interface {
+	Board(board, placed []int)
+	Events(events []ev)
+	Acquired(acq []int, remaining, score int)
+	Summary(res gameResult)
+}
+
+// cellWidth sets the width of the board cells of the text output. Zero fits the longest label in play.
+var cellWidth = flag.Int("cell-width", 0, "width of the board cells in the text output (0 to fit the longest label)")
+
+// newRenderer function returns the renderer selected by the -format and -summary-only-json flags.
+func newRenderer(w io.Writer) Renderer {
+	switch *format {
+	case "text":
+		if *describe {
+			return &DescribeRenderer{w}
+		}
+		if *dashboard && w == os.Stdout && isTerminal(os.Stdout) {
+			return newDashboardRenderer(w)
+		}
+		return newTextRenderer(w, *eventsCompact)
+	case "json":
+		return &JSONRenderer{enc: json.NewEncoder(w), summaryOnly: *summaryOnlyJSON}
+	}
+	die("-format must be text or json, got %q", *format)
+	return nil
+}
+
+// emptyLabel is the label shown for the empty slots of the board in the text output.
+var emptyLabel = flag.String("empty-label", "Empty", "label shown for the empty slots of the board")
+
+// debugIndices prefixes each cell of the board in the text output with the index of its slot, e.g. "0:Red", as used
+// by the code and by the slot order printed under -v.
+var debugIndices = flag.Bool("debug-indices", false, "prefix each cell of the board with the index of its slot")
+
+// quiet hides the optional details of each turn in the text output, such as the running score.
+var quiet = flag.Bool("quiet", false, "hide the optional details of each turn, such as the running score")
+
+// plain disables the decorations of the text output, such as the marks around the toys just placed.
+var plain = flag.Bool("plain", false, "print the text output without decorations")
+
+// sortAcquired orders the colors of the acquired tallies of the text output: "color" in palette order, "count" by
+// descending count, ties in palette order.
+var sortAcquired = flag.String("sort-acquired", "color", "order of the acquired tallies: color or count")
+
+// hideZero leaves the colors without any toy acquired out of the acquired tallies of the text output and of the JSON
+// summary. The totals still count every toy.
+var hideZero = flag.Bool("hide-zero", false, "leave the colors without any toy out of the acquired tallies")
+
+// acquiredOrder function returns the colors (0-based) of acq, indexed by color, in the order of -sort-acquired,
+// without the colors of zero count under -hide-zero.
+func acquiredOrder(acq []int) []int {
+	order := make([]int, 0, len(acq))
+	for k, v := range acq {
+		if v > 0 || !*hideZero {
+			order = append(order, k)
+		}
+	}
+	if *sortAcquired == "count" {
+		slices.SortStableFunc(order, func(a, b int) int { return acq[b] - acq[a] })
+	}
+	return order
+}
+
+// TextRenderer renders the game as human-readable text sections.
+// With compact set, the events of a turn are printed on a single line instead of the banner format.
+// Each slot of the board is padded to cellWidth characters. With highlight set, the toys placed during the turn are
+// shown in brackets.
+type TextRenderer struct {
+	w         io.Writer
+	compact   bool
+	cellWidth int
+	highlight bool
+	// score is the score at the end of the last turn rendered, from which the events of the turn are counted.
+	score int
+}
+
+// newTextRenderer function returns a TextRenderer writing to w, configured by the flags.
+func newTextRenderer(w io.Writer, compact bool) *TextRenderer {
+	return &TextRenderer{w: w, compact: compact, cellWidth: boardCellWidth(), highlight: !*plain}
+}
+
+// boardCellWidth function returns the width of a board cell: the -cell-width flag if set,
+// otherwise the length of the longest label that can appear on the board.
+func boardCellWidth() int {
+	if *cellWidth > 0 {
+		return *cellWidth
+	}
+	width := utf8.RuneCountInString(*emptyLabel)
+	for _, c := range colors[:*numColors] {
+		width = max(width, utf8.RuneCountInString(c))
+	}
+	return width
+}
+
+// Board method prints the current state of the board, showing the items (e.g., colors) placed in each slot.
+// If a slot is empty, it prints the -empty-label flag ("Empty" by default) for that slot.
+// The board is printed in a grid format, with 3 items per row.
+// Unless highlighting is off, the toys in the placed slots are shown in brackets, e.g. "[Red]".
+// Under -v the hash of the board follows, see boardHash.
+func (r *TextRenderer) Board(board, placed []int) {
+	_, _ = fmt.Fprintln(r.w, "========== board ==========")
+	width := r.cellWidth
+	if r.highlight {
+		width += 2
+	}
+	if *debugIndices {
+		width += len(strconv.Itoa(len(board)-1)) + 1
+	}
+	for i, v := range board {
+		label := *emptyLabel
+		if v > 0 {
+			label = colors[v-1]
+		}
+		if r.highlight && v > 0 && slices.Contains(placed, i) {
+			label = "[" + label + "]"
+		} else if r.highlight {
+			label = " " + label
+		}
+		if *debugIndices {
+			label = fmt.Sprintf("%d:%s", i, label)
+		}
+		_, _ = fmt.Fprintf(r.w, "%-*s ", width, label)
+		if i%3 == 2 {
+			_, _ = fmt.Fprint(r.w, "\n")
+		}
+	}
+	if *verbose {
+		_, _ = fmt.Fprintf(r.w, "Board hash: %s\n", boardHash(board))
+	}
+}
+
+// Events method prints the details of each event in the provided events list.
+// It displays the event description and the associated reward for each event, followed, unless -quiet is set, by the
+// running score once the event is awarded.
+func (r *TextRenderer) Events(events []ev) {
+	if r.compact {
+		r.eventsCompact(events)
+		return
+	}
+	if len(events) != 0 {
+		_, _ = fmt.Fprintln(r.w, "========== events ==========")
+	}
+	score := r.score
+	for _, e := range events {
+		score += eventRewardRules[e.event]
+		if *quiet {
+			_, _ = fmt.Fprintf(r.w, "Event: %-20s +%d\n", eventLabel(e.event), eventRewardRules[e.event])
+			continue
+		}
+		_, _ = fmt.Fprintf(r.w, "Event: %-20s +%-3d Score: %d\n", eventLabel(e.event), eventRewardRules[e.event], score)
+	}
+}
+
+// eventsCompact method prints all events of a turn on a single line, e.g. "Events: LuckyStrike(Red) +3, OnePair(Blue) +1".
+// Events that concern a single color show it in parentheses; nothing is printed for a turn without events.
+// The custom messages of -messages are printed as they are, while the names of the events are printed without spaces.
+func (r *TextRenderer) eventsCompact(events []ev) {
+	if len(events) == 0 {
+		return
+	}
+	items := make([]string, 0, len(events))
+	for _, e := range events {
+		name := eventLabel(e.event)
+		if _, ok := eventMessages[e.event]; !ok {
+			name = strings.ReplaceAll(name, " ", "")
+		}
+		if len(e.acquired) == 1 {
+			for k := range e.acquired {
+				name = fmt.Sprintf("%s(%s)", name, colors[k-1])
+			}
+		}
+		items = append(items, fmt.Sprintf("%s +%d", name, eventRewardRules[e.event]))
+	}
+	_, _ = fmt.Fprintf(r.w, "Events: %s\n", strings.Join(items, ", "))
+}
+
+// Acquired method prints the list of acquired items (e.g., toys) along with their quantities and their targets if any,
+// followed by the number of toys that remain to be placed and, unless -quiet is set, the points scored so far.
+func (r *TextRenderer) Acquired(acq []int, remaining, score int) {
+	_, _ = fmt.Fprintln(r.w, "========== acquired ==========")
+	for _, k := range acquiredOrder(acq) {
+		_, _ = fmt.Fprintf(r.w, "%s: %s; ", colors[k], acquiredLabel(acq[k], k))
+	}
+	_, _ = fmt.Fprintf(r.w, "Remaining: %d\n", remaining)
+	r.score = score
+	if !*quiet {
+		_, _ = fmt.Fprintf(r.w, "Score: %d\n", score)
+	}
+}
+
+// Summary method prints the final list of acquired items, the total number of acquired items
+// and the metrics collected over the game.
+func (r *TextRenderer) Summary(res gameResult) {
+	_, _ = fmt.Fprintln(r.w, "========== acquired ==========")
+	acq := make([]int, *numColors)
+	for k, c := range colors[:*numColors] {
+		acq[k] = res.Acquired[c]
+	}
+	for _, k := range acquiredOrder(acq) {
+		_, _ = fmt.Fprintf(r.w, "%s: %s; ", colors[k], acquiredLabel(acq[k], k))
+	}
+	_, _ = fmt.Fprintf(r.w, "\nYou have received %d toys\n", res.Total)
+	if *bigTotal && !*quiet {
+		printBigNumber(r.w, res.Total)
+	}
+	_, _ = fmt.Fprintf(r.w, "Score: %d points\n", res.Score)
+	_, _ = fmt.Fprintf(r.w, "Game ended: %s\n", res.TerminationReason)
+	if weightedDraws() {
+		_, _ = fmt.Fprintf(r.w, "Rarity score: %.2f (1.00 matches the draw odds)\n", res.Rarity)
+	}
+	_, _ = fmt.Fprintf(r.w, "Peak distinct colors on board: %d\n", res.PeakDistinctColors)
+	_, _ = fmt.Fprintf(r.w, "Longest dry streak: %s\n", quantity(res.LongestDryStreak, "turn"))
+	_, _ = fmt.Fprintf(r.w, "Most pairs in a turn: %d\n", res.MaxPairsPerTurn)
+	_, _ = fmt.Fprintf(r.w, "Slots in several matches at once: %d, at most %d in a turn\n", res.OverlappingSlots, res.MaxOverlapsPerTurn)
+	_, _ = fmt.Fprintf(r.w, "Efficiency: %.3f events per toy placed\n", res.Efficiency)
+	if n, ok := res.Closest[eventNames[eventAllDifferent]]; ok {
+		_, _ = fmt.Fprintf(r.w, "Closest to a Family Portrait: %d distinct colors of %d\n", n, len(initialOrderedSlots))
+	}
+	if n, ok := res.Closest[eventNames[eventClear]]; ok {
+		_, _ = fmt.Fprintf(r.w, "Closest to Clear The Board: %d slots of %d freed in a turn\n", n, len(initialOrderedSlots))
+	}
+	if res.Spend != nil {
+		_, _ = fmt.Fprintf(r.w, "Toy value: %.2f; Package cost: %.2f; Net value: %.2f\n",
+			res.Spend.ToyValue, res.Spend.PackageCost, res.Spend.Net)
+	}
+}
+
+// JSONRenderer renders the game as a stream of JSON objects: one object per turn, then the final gameResult.
+// With summaryOnly set, the per-turn objects are suppressed and the output is a single JSON object.
+type JSONRenderer struct {
+	enc         *json.Encoder
+	summaryOnly bool
+	turn        jsonTurn
+}
+
+// jsonTurn is the JSON object emitted for each turn.
+type jsonTurn struct {
+	Turn      int            `json:"turn"`
+	Board     []string       `json:"board"`
+	BoardHash string         `json:"board_hash,omitempty"`
+	Placed    []int          `json:"placed"`
+	Events    []jsonEvent    `json:"events"`
+	Acquired  map[string]int `json:"acquired"`
+	Remaining int            `json:"remaining"`
+	Score     int            `json:"score"`
+}
+
+// jsonEvent is the JSON representation of an event.
+type jsonEvent struct {
+	Event    string         `json:"event"`
+	Reward   int            `json:"reward"`
+	Acquired map[string]int `json:"acquired"`
+}
+
+// Board method records the board of the current turn and the slots filled during the turn, with the hash of the board
+// under -v.
+func (r *JSONRenderer) Board(board, placed []int) {
+	r.turn.Turn++
+	r.turn.Board = boardLabels(board)
+	if *verbose {
+		r.turn.BoardHash = boardHash(board)
+	}
+	r.turn.Placed = placed
+}
+
+// Events method records the events of the current turn.
+func (r *JSONRenderer) Events(events []ev) {
+	r.turn.Events = make([]jsonEvent, 0, len(events))
+	for _, e := range events {
+		acq := make(map[string]int, len(e.acquired))
+		for k, v := range e.acquired {
+			acq[colors[k-1]] = v
+		}
+		r.turn.Events = append(r.turn.Events, jsonEvent{eventDesc[e.event], eventRewardRules[e.event], acq})
+	}
+}
+
+// Acquired method completes the current turn and emits it.
+func (r *JSONRenderer) Acquired(acq []int, remaining, score int) {
+	r.turn.Acquired = colorTally(acq)
+	r.turn.Remaining = remaining
+	r.turn.Score = score
+	if !r.summaryOnly {
+		r.encode(r.turn)
+	}
+}
+
+// Summary method emits the final outcome of the game, without the colors of zero count under -hide-zero.
+func (r *JSONRenderer) Summary(res gameResult) {
+	if *hideZero {
+		res.Acquired = maps.Clone(res.Acquired)
+		maps.DeleteFunc(res.Acquired, func(_ string, v int) bool { return v == 0 })
+	}
+	r.encode(res)
+}
+
+func (r *JSONRenderer) encode(v any) {
+	if err := r.enc.Encode(v); err != nil {
+		die("print json failed, %v", err)
+	}
+}
+
+// boardLabels function returns the color of each slot of the board, with an empty string for empty slots.
+func boardLabels(board []int) []string {
+	labels := make([]string, len(board))
+	for k, v := range board {
+		if v > 0 {
+			labels[k] = colors[v-1]
+		}
+	}
+	return labels
+}
+
+// boardHash function returns a short stable hash of board, the 32-bit FNV-1a hash of its slots in hexadecimal,
+// so that two runs can be compared turn by turn without comparing whole boards.
+func boardHash(board []int) string {
+	h := fnv.New32a()
+	for _, v := range board {
+		_, _ = h.Write([]byte{byte(v)})
+	}
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// colorTally function converts acq, indexed by color (0-based), into a color-name keyed tally.
+func colorTally(acq []int) map[string]int {
+	tally := make(map[string]int, len(acq))
+	for k, v := range acq {
+		tally[colors[k]] = v
+	}
+	return tally
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTextRendererEventsCompact(t *testing.T) {
+	var buf bytes.Buffer
+	r := newTextRenderer(&buf, true)
+	r.Events([]ev{{map[int]int{1: 3}, eventLuckyStrike}, {map[int]int{8: 2}, eventOnePair}})
+	if got, want := buf.String(), "Events: LuckyStrike(Red) +3, OnePair(Blue) +1\n"; got != want {
+		t.Errorf("compact events = %q, want %q", got, want)
+	}
+	buf.Reset()
+	r.Events(nil)
+	if buf.Len() != 0 {
+		t.Errorf("compact events of a turn without events = %q, want nothing", buf.String())
+	}
+}
+
+func TestJSONRendererSummaryOnly(t *testing.T) {
+	useSeed(t, 1)
+	setFlag(t, "max-toys", "5")
+	var buf bytes.Buffer
+	res := playGame(newGame(1, 30), &JSONRenderer{enc: json.NewEncoder(&buf), summaryOnly: true})
+	if res.TerminationReason != endBoxFull {
+		t.Fatalf("the game ended with %q, want %q", res.TerminationReason, endBoxFull)
+	}
+	dec := json.NewDecoder(&buf)
+	var summary map[string]any
+	if err := dec.Decode(&summary); err != nil {
+		t.Fatalf("decode the output: %v", err)
+	}
+	if dec.More() {
+		t.Error("the output holds more than one JSON value")
+	}
+	if summary["total"] != float64(res.Total) {
+		t.Errorf("total = %v, want %d", summary["total"], res.Total)
+	}
+}
+
+func TestTextRendererSummaryNumColors(t *testing.T) {
+	setFlag(t, "num-colors", "5")
+	var buf bytes.Buffer
+	newTextRenderer(&buf, false).Summary(gameResult{Acquired: colorTally(make([]int, 5))})
+	out := buf.String()
+	if !strings.Contains(out, colors[4]+": 0") {
+		t.Errorf("the summary leaves out %s, a color in play:\n%s", colors[4], out)
+	}
+	if strings.Contains(out, colors[5]) {
+		t.Errorf("the summary lists %s, a color out of play:\n%s", colors[5], out)
+	}
+}
+
+func TestRenderersShareGameLogic(t *testing.T) {
+	var text, stream bytes.Buffer
+	useSeed(t, 7)
+	textRes := playGame(newGame(2, 18), newTextRenderer(&text, false))
+	useSeed(t, 7)
+	jsonRes := playGame(newGame(2, 18), &JSONRenderer{enc: json.NewEncoder(&stream)})
+	if !reflect.DeepEqual(textRes, jsonRes) {
+		t.Errorf("the outcome depends on the renderer:\ntext %+v\njson %+v", textRes, jsonRes)
+	}
+	dec := json.NewDecoder(&stream)
+	objects := 0
+	for dec.More() {
+		var v map[string]any
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("decode the JSON output: %v", err)
+		}
+		objects++
+	}
+	if turns := strings.Count(text.String(), "========== board =========="); objects != turns+1 {
+		t.Errorf("the JSON output holds %d objects, want one per turn and the summary, %d", objects, turns+1)
+	}
+}
+
+// columnStarts function returns the offset of the first rune of each word of line.
+func columnStarts(line string) []int {
+	starts := make([]int, 0)
+	prev := ' '
+	for k, c := range []rune(line) {
+		if c != ' ' && prev == ' ' {
+			starts = append(starts, k)
+		}
+		prev = c
+	}
+	return starts
+}
+
+func TestTextRendererBoardAligned(t *testing.T) {
+	setFlag(t, "plain", "true")
+	for _, tc := range []struct{ name, numColors, emptyLabel string }{
+		{"short labels", "2", "-"},
+		{"long labels", "10", "Empty"},
+	} {
+		setFlag(t, "num-colors", tc.numColors)
+		setFlag(t, "empty-label", tc.emptyLabel)
+		var buf bytes.Buffer
+		r := newTextRenderer(&buf, false)
+		n := *numColors
+		r.Board([]int{1, n, 0, n, 0, 1, 0, 1, n}, nil)
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")[1:]
+		want := []int{0, r.cellWidth + 1, 2 * (r.cellWidth + 1)}
+		for _, line := range lines {
+			if got := columnStarts(line); !slices.Equal(got, want) {
+				t.Errorf("%s: columns of %q start at %v, want %v", tc.name, line, got, want)
+			}
+		}
+		if longest := len(colors[n-1]); tc.name == "long labels" && r.cellWidth < longest {
+			t.Errorf("%s: cell width = %d, want at least %d", tc.name, r.cellWidth, longest)
+		}
+	}
+}
+
+func TestTextRendererBoardHighlightsPlaced(t *testing.T) {
+	for _, plain := range []string{"false", "true"} {
+		setFlag(t, "plain", plain)
+		g := newGame(1, 30)
+		copy(g.board, []int{4, 5, 6})
+		g.orderedEmptySlots, g.remaining = emptySlots(g.board), 2
+		draws := []int{7, 8}
+		g.draw = func() int {
+			c := draws[0]
+			draws = draws[1:]
+			return c
+		}
+		var buf bytes.Buffer
+		g.playTurn(newTextRenderer(&buf, false))
+		board := strings.SplitN(buf.String(), "========== board ==========\n", 2)[1]
+		marked := strings.Count(board, "[")
+		if plain == "true" {
+			if marked != 0 {
+				t.Errorf("-plain board marks %d slots, want none:\n%s", marked, board)
+			}
+			continue
+		}
+		if marked != 2 || !strings.Contains(board, "[Pink]") || !strings.Contains(board, "[Blue]") {
+			t.Errorf("board marks %d slots, want the slots of Pink and Blue placed this turn:\n%s", marked, board)
+		}
+	}
+}
+
+func TestTextRendererBoardEmptyLabel(t *testing.T) {
+	setFlag(t, "plain", "true")
+	setFlag(t, "empty-label", "·")
+	var buf bytes.Buffer
+	newTextRenderer(&buf, false).Board([]int{1, 0, 0, 0, 0, 0, 0, 0, 2}, nil)
+	board := buf.String()
+	if n := strings.Count(board, "·"); n != 7 {
+		t.Errorf("the board shows the empty label %d times, want 7:\n%s", n, board)
+	}
+	if strings.Contains(board, "Empty") {
+		t.Errorf("the board shows the default empty label:\n%s", board)
+	}
+}
+
+func TestTextRendererRunningScore(t *testing.T) {
+	for _, quiet := range []string{"false", "true"} {
+		setFlag(t, "quiet", quiet)
+		useSeed(t, demoSeed)
+		var buf bytes.Buffer
+		g := newGame(1, 30)
+		for !g.over() {
+			g.playTurn(newTextRenderer(&buf, false))
+		}
+		sum, turns := 0, 0
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if rest, ok := strings.CutPrefix(line, "Event: "); ok {
+				points, _, _ := strings.Cut(rest[strings.Index(rest, "+")+1:], " ")
+				n, err := strconv.Atoi(points)
+				if err != nil {
+					t.Fatalf("parse the points of %q: %v", line, err)
+				}
+				sum += n
+			}
+			if rest, ok := strings.CutPrefix(line, "Score: "); ok {
+				turns++
+				if rest != strconv.Itoa(sum) {
+					t.Errorf("turn %d: score %s, want the sum of the event points so far, %d", turns, rest, sum)
+				}
+			}
+		}
+		if quiet == "true" && turns != 0 {
+			t.Errorf("-quiet prints the score of %d turns, want none", turns)
+		}
+		if quiet == "false" && turns != g.turn {
+			t.Errorf("printed the score of %d turns, want every turn, %d", turns, g.turn)
+		}
+	}
+}
+
+func TestBoardHash(t *testing.T) {
+	board := []int{1, 2, 3, 0, 0, 4, 5, 0, 9}
+	if a, b := boardHash(board), boardHash(slices.Clone(board)); a != b {
+		t.Errorf("identical boards hash to %s and %s", a, b)
+	}
+	for k := range board {
+		other := slices.Clone(board)
+		other[k] = (other[k] + 1) % 10
+		if boardHash(other) == boardHash(board) {
+			t.Errorf("boards %v and %v hash equal", board, other)
+		}
+	}
+}
+
+// knownTally function returns the outcome of a game with a known tally: 1 Red, 4 Purple, 4 Orange, 2 Blue.
+func knownTally() gameResult {
+	acq := make([]int, *numColors)
+	acq[0], acq[2], acq[3], acq[7] = 1, 4, 4, 2
+	return gameResult{Acquired: colorTally(acq), Total: 11}
+}
+
+func TestTextRendererSummarySortByCount(t *testing.T) {
+	setFlag(t, "sort-acquired", "count")
+	var buf bytes.Buffer
+	newTextRenderer(&buf, false).Summary(knownTally())
+	line := strings.Split(buf.String(), "\n")[1]
+	// Purple and Orange tie, and stay in palette order.
+	if want := "Purple: 4; Orange: 4; Blue: 2; Red: 1; Yellow: 0; "; !strings.HasPrefix(line, want) {
+		t.Errorf("tallies by count = %q, want them to start with %q", line, want)
+	}
+}
+
+func TestSummaryHideZero(t *testing.T) {
+	for _, hide := range []bool{false, true} {
+		setFlag(t, "hide-zero", strconv.FormatBool(hide))
+		var text, stream bytes.Buffer
+		newTextRenderer(&text, false).Summary(knownTally())
+		(&JSONRenderer{enc: json.NewEncoder(&stream)}).Summary(knownTally())
+		var summary struct {
+			Acquired map[string]int `json:"acquired"`
+			Total    int            `json:"total"`
+		}
+		if err := json.Unmarshal(stream.Bytes(), &summary); err != nil {
+			t.Fatalf("decode the summary: %v", err)
+		}
+		_, listed := summary.Acquired["Yellow"]
+		if shown := strings.Contains(text.String(), "Yellow: 0"); shown == hide || listed == hide {
+			t.Errorf("-hide-zero=%v: Yellow, without toys, in the text %v and in the JSON %v, want %v",
+				hide, shown, listed, !hide)
+		}
+		if !strings.Contains(text.String(), "You have received 11 toys") || summary.Total != 11 {
+			t.Errorf("-hide-zero=%v: the total leaves out toys:\n%s", hide, text.String())
+		}
+	}
+}
+
+func TestTextRendererEventsRunningScore(t *testing.T) {
+	var buf bytes.Buffer
+	r := newTextRenderer(&buf, false)
+	r.Acquired(make([]int, *numColors), 10, 5)
+	buf.Reset()
+	events := []ev{
+		{map[int]int{1: 1}, eventLuckyColor},
+		{map[int]int{2: 3}, eventLuckyStrike},
+		{map[int]int{4: 2}, eventOnePair},
+		{map[int]int{}, eventClear},
+	}
+	r.Events(events)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")[1:]
+	if len(lines) != len(events) {
+		t.Fatalf("printed %d event lines, want %d:\n%s", len(lines), len(events), buf.String())
+	}
+	score := 5
+	for k, e := range events {
+		delta := eventRewardRules[e.event]
+		score += delta
+		if want := fmt.Sprintf("+%-3d Score: %d", delta, score); !strings.HasSuffix(lines[k], want) {
+			t.Errorf("event line %q, want it to end with %q", lines[k], want)
+		}
+	}
+}
+
+func TestTextRendererDebugIndices(t *testing.T) {
+	setFlag(t, "debug-indices", "true")
+	setFlag(t, "plain", "true")
+	var buf bytes.Buffer
+	newTextRenderer(&buf, false).Board([]int{1, 0, 8, 0, 2, 0, 3, 3, 0}, nil)
+	rows := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")[1:]
+	want := [][]string{{"0:Red", "1:Empty", "2:Blue"}, {"3:Empty", "4:Yellow", "5:Empty"}, {"6:Purple", "7:Purple", "8:Empty"}}
+	if len(rows) != len(want) {
+		t.Fatalf("board =\n%s\nwant %d rows", buf.String(), len(want))
+	}
+	for k, row := range rows {
+		if got := strings.Fields(row); !slices.Equal(got, want[k]) {
+			t.Errorf("row %d = %q, want %q", k, got, want[k])
+		}
+	}
+}
+
+func TestTextRendererSummaryDryStreak(t *testing.T) {
+	for n, want := range map[int]string{0: "Longest dry streak: 0 turns\n", 1: "Longest dry streak: 1 turn\n", 3: "Longest dry streak: 3 turns\n"} {
+		var buf bytes.Buffer
+		newTextRenderer(&buf, false).Summary(gameResult{Acquired: colorTally(make([]int, *numColors)), LongestDryStreak: n})
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("the summary of a longest dry streak of %d leaves out %q:\n%s", n, want, buf.String())
+		}
+	}
+}
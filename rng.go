@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+)
+
+// countingSource is a rand.Source that counts the values it produces. For a given seed, a change in the count across
+// versions means that the game consumes randomness differently, and thus plays differently.
+type countingSource struct {
+	src   *rand.PCG
+	calls uint64
+}
+
+// Uint64 method returns the next value of the wrapped source and counts it.
+func (s *countingSource) Uint64() uint64 {
+	s.calls++
+	return s.src.Uint64()
+}
+
+// seedPhrase seeds the game with a memorable phrase instead of a number, see seedFromString.
+var seedPhrase = flag.String("seed-from-string", "", "seed the random draws with the hash of this phrase, instead of -seed")
+
+// seedFromString function returns the seed of a phrase: its FNV-64a hash, so that a phrase always plays the same game.
+func seedFromString(phrase string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(phrase))
+	return h.Sum64()
+}
+
+// rngSource is the source behind rng, see newRNG.
+var rngSource *countingSource
+
+// newRNG function returns a generator seeded with seed, and makes its counting source the current rngSource.
+func newRNG(seed uint64) *rand.Rand {
+	rngSource = &countingSource{src: rand.NewPCG(seed, seed)}
+	return rand.New(rngSource)
+}
+
+// defaultSeed function returns the seed used when -seed is not set, derived from the current time of now.
+func defaultSeed() uint64 {
+	return uint64(now().UnixNano())
+}
+
+// rngCheckpoint is a snapshot of the state behind the draws of the game: the generator, and the draw state of
+// the -anti-repeat and -finite-stock modes.
+type rngCheckpoint struct {
+	state     []byte
+	calls     uint64
+	lastDrawn int
+	stock     []int
+}
+
+// checkpointRNG function returns a snapshot of the state behind the draws, to be restored with restoreRNG.
+func checkpointRNG() rngCheckpoint {
+	state, err := rngSource.src.MarshalBinary()
+	if err != nil {
+		die("checkpoint the generator failed, %v", err)
+	}
+	return rngCheckpoint{state, rngSource.calls, lastDrawn, slices.Clone(stock)}
+}
+
+// restoreRNG function restores a snapshot of checkpointRNG, so that the next draws are those that followed the snapshot.
+func restoreRNG(c rngCheckpoint) {
+	if err := rngSource.src.UnmarshalBinary(c.state); err != nil {
+		die("restore the generator failed, %v", err)
+	}
+	rngSource.calls, lastDrawn, stock = c.calls, c.lastDrawn, slices.Clone(c.stock)
+}
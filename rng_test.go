@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRestoreRNGReproducesDraws(t *testing.T) {
+	useSeed(t, 21)
+	useStock(t, "3")
+	setFlag(t, "anti-repeat", "true")
+	for range 5 {
+		drawColor(1)
+	}
+	c := checkpointRNG()
+	next := make([]int, 10)
+	for k := range next {
+		next[k] = drawColor(1)
+	}
+	calls := rngSource.calls
+	restoreRNG(c)
+	again := make([]int, 10)
+	for k := range again {
+		again[k] = drawColor(1)
+	}
+	if !slices.Equal(again, next) {
+		t.Errorf("draws after the restore = %v, want %v", again, next)
+	}
+	if rngSource.calls != calls {
+		t.Errorf("RNG calls after the restore = %d, want %d", rngSource.calls, calls)
+	}
+}
+
+func TestDefaultSeedFromClock(t *testing.T) {
+	fixed := time.Date(2024, 3, 1, 12, 0, 0, 42, time.UTC)
+	useClock(t, func() time.Time { return fixed })
+	if got, want := defaultSeed(), uint64(fixed.UnixNano()); got != want {
+		t.Errorf("default seed = %d, want %d", got, want)
+	}
+}
+
+func TestSeedFromString(t *testing.T) {
+	if a, b := seedFromString("my lucky phrase"), seedFromString("my lucky phrase"); a != b {
+		t.Errorf("the same phrase seeds %d and %d", a, b)
+	}
+	if a, b := seedFromString("my lucky phrase"), seedFromString("my lucky phrases"); a == b {
+		t.Errorf("two phrases both seed %d", a)
+	}
+	// The FNV-64a hash of the empty phrase is its offset basis.
+	if got := seedFromString(""); got != 0xcbf29ce484222325 {
+		t.Errorf("seed of the empty phrase = %#x, want the FNV-64a offset basis", got)
+	}
+}
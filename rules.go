@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// eventsFile loads custom events, each declared on its own line as "Name | condition | points":
+//
+//	# Blank lines and lines starting with # are ignored.
+//	Four Of A Kind | 4-of-a-color | 4
+//	Neighbours | 2-in-a-row | 1
+//	Rainbow | all-distinct-full-board | 2
+//
+// The conditions are "N-of-a-color" (at least N toys of one color anywhere on the board), "N-in-a-row"
+// (N consecutive toys of one color along a row, a column or a diagonal) and "all-distinct-full-board".
+// Custom events are checked on the board right after the toys of a turn were placed, before the built-in events are
+// resolved, and fire at most once per turn each. They award points only: they neither clear slots nor acquire toys.
+// For that reason the built-in events are not expressed in this format and there is no default events file: Lucky
+// Color fires on a draw rather than on the board, and the other built-in events clear and acquire the toys they match.
+var eventsFile = flag.String("events-file", "", "load custom events from this file")
+
+// customEvent is a custom event compiled from the events file.
+type customEvent struct {
+	// event is the event type, indexing eventDesc, eventNames and eventRewardRules like the built-in events.
+	event int
+	// detect reports whether the event fires on board, and the color it concerns (1-based) or 0.
+	detect func(board []int) (bool, int)
+}
+
+// customEvents holds the custom events loaded from the events file, in file order.
+var customEvents []customEvent
+
+// loadCustomEvents function compiles the custom events of the file at path and registers them next to the built-in events.
+func loadCustomEvents(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "|")
+		if len(fields) != 3 {
+			return fmt.Errorf("%s:%d: want \"Name | condition | points\", got %q", path, line, text)
+		}
+		name, cond := strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+		points, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil || points < 0 {
+			return fmt.Errorf("%s:%d: invalid points %q", path, line, strings.TrimSpace(fields[2]))
+		}
+		detect, err := compileCondition(cond)
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		customEvents = append(customEvents, customEvent{registerEvent(name, cond+", %d more toys to place", points), detect})
+	}
+	return sc.Err()
+}
+
+// registerEvent function registers an event next to the built-in events and returns its event type.
+// The explanation narrates the event in the demo and is formatted with its points.
+func registerEvent(name, explanation string, points int) int {
+	eventDesc = append(eventDesc, name)
+	eventNames = append(eventNames, strings.ToLower(strings.Join(strings.Fields(name), "-")))
+	eventRewardRules[len(eventDesc)-1] = points
+	eventExplanations = append(eventExplanations, explanation)
+	return len(eventDesc) - 1
+}
+
+// compileCondition function compiles a condition of the events file into a detector.
+func compileCondition(cond string) (func(board []int) (bool, int), error) {
+	if cond == "all-distinct-full-board" {
+		return func(board []int) (bool, int) {
+			return distinctColors(board) == len(board), 0
+		}, nil
+	}
+	count, kind, ok := strings.Cut(cond, "-")
+	n, err := strconv.Atoi(count)
+	if !ok || err != nil || n < 1 {
+		return nil, fmt.Errorf("unknown condition %q", cond)
+	}
+	switch kind {
+	case "of-a-color":
+		return func(board []int) (bool, int) {
+			counts := make(map[int]int)
+			for _, v := range board {
+				if v > 0 {
+					if counts[v]++; counts[v] >= n {
+						return true, v
+					}
+				}
+			}
+			return false, 0
+		}, nil
+	case "in-a-row":
+		if n > boardSize {
+			return nil, fmt.Errorf("condition %q: a line holds at most %d toys", cond, boardSize)
+		}
+		return func(board []int) (bool, int) {
+			for _, comb := range tripleCombination {
+				run := 0
+				for k, slot := range comb {
+					if board[slot] == 0 || (k > 0 && board[slot] != board[comb[k-1]]) {
+						run = 0
+					}
+					if board[slot] != 0 {
+						run++
+					}
+					if run >= n {
+						return true, board[slot]
+					}
+				}
+			}
+			return false, 0
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown condition %q", cond)
+}
+
+// detectCustomEvents function appends the custom events that fire on board to events.
+func detectCustomEvents(board []int, events []ev) []ev {
+	for _, c := range customEvents {
+		if ok, color := c.detect(board); ok {
+			acq := map[int]int{}
+			if color > 0 {
+				acq[color] = 0
+			}
+			events = append(events, ev{acq, c.event})
+		}
+	}
+	return events
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCustomEventFourOfAKind(t *testing.T) {
+	saveEvents(t)
+	customEvents = nil
+	path := filepath.Join(t.TempDir(), "events.txt")
+	if err := os.WriteFile(path, []byte("# custom events\nFour Of A Kind | 4-of-a-color | 4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadCustomEvents(path); err != nil {
+		t.Fatalf("load the events: %v", err)
+	}
+	fourOfAKind := customEvents[0].event
+	if eventRewardRules[fourOfAKind] != 4 {
+		t.Errorf("Four Of A Kind awards %d points, want 4", eventRewardRules[fourOfAKind])
+	}
+
+	events := detectCustomEvents([]int{8, 1, 8, 2, 8, 3, 4, 8, 5}, nil)
+	if len(events) != 1 || events[0].event != fourOfAKind {
+		t.Fatalf("events on a board of four Blue toys = %v, want Four Of A Kind", events)
+	}
+	if _, ok := events[0].acquired[8]; !ok {
+		t.Errorf("Four Of A Kind concerns %v, want Blue", events[0].acquired)
+	}
+	if events := detectCustomEvents([]int{8, 1, 8, 2, 8, 3, 4, 6, 5}, nil); len(events) != 0 {
+		t.Errorf("events on a board of three Blue toys = %v, want none", events)
+	}
+}
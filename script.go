@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// scriptPath drives the prompts of the game from a file of commands instead of the keyboard, one command per line:
+//
+//	# Blank lines and lines starting with # are ignored.
+//	select-color Red
+//	select-package 30
+//	continue
+//	quit
+//
+// select-color and select-package answer the selection prompts; continue answers the prompts between turns and
+// before a reset under -pause-on-reset, and quit cancels the game there. The game quits when the script runs out of
+// commands between turns; running out before a selection is an error.
+var scriptPath = flag.String("script", "", "drive the prompts of the game from this file of commands")
+
+// script holds the commands of -script still to be played, or nil when the game is driven by the keyboard.
+var script *gameScript
+
+// scriptCommand is a command of the script, with the line it was read from.
+type scriptCommand struct {
+	line int
+	name string
+	arg  string
+}
+
+// gameScript is a sequence of commands driving the prompts of the game.
+type gameScript struct {
+	path     string
+	commands []scriptCommand
+}
+
+// loadScript function reads and checks the commands of the script at path.
+func loadScript(path string) (*gameScript, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	s := &gameScript{path: path}
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, arg, _ := strings.Cut(text, " ")
+		cmd := scriptCommand{line, name, strings.TrimSpace(arg)}
+		if err := checkScriptCommand(cmd); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		s.commands = append(s.commands, cmd)
+	}
+	return s, sc.Err()
+}
+
+// checkScriptCommand function checks the name and the argument of a command of the script.
+func checkScriptCommand(cmd scriptCommand) error {
+	switch cmd.name {
+	case "select-color":
+		if !slices.Contains(colors[:*numColors], cmd.arg) {
+			return fmt.Errorf("select-color: %q is not a color in play", cmd.arg)
+		}
+	case "select-package":
+		if n, err := strconv.Atoi(cmd.arg); err != nil || !slices.Contains(packages, n) {
+			return fmt.Errorf("select-package: %q is not a package, want one of %v", cmd.arg, packages)
+		}
+	case "continue", "quit":
+		if cmd.arg != "" {
+			return fmt.Errorf("%s takes no argument", cmd.name)
+		}
+	case "undo", "save":
+		return fmt.Errorf("%s is not supported", cmd.name)
+	default:
+		return fmt.Errorf("unknown command %q", cmd.name)
+	}
+	return nil
+}
+
+// take method returns the next command of the script, which must be one of names, and reports whether the script
+// still had a command. A command of another kind is fatal.
+func (s *gameScript) take(names ...string) (scriptCommand, bool) {
+	if len(s.commands) == 0 {
+		return scriptCommand{}, false
+	}
+	cmd := s.commands[0]
+	s.commands = s.commands[1:]
+	if !slices.Contains(names, cmd.name) {
+		die("%s:%d: want %s, got %s", s.path, cmd.line, strings.Join(names, " or "), cmd.name)
+	}
+	return cmd, true
+}
+
+// selection method returns the argument of the next command of the script, which must be the selection name.
+func (s *gameScript) selection(name string) string {
+	cmd, ok := s.take(name)
+	if !ok {
+		die("%s: the script ended before %s", s.path, name)
+	}
+	return cmd.arg
+}
+
+// proceed method reports whether the next command of the script continues the game, as next and confirmReset do.
+func (s *gameScript) proceed() bool {
+	cmd, ok := s.take("continue", "quit")
+	return ok && cmd.name == "continue"
+}
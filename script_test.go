@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useScript function drives the prompts with the commands for the duration of the test, see -script.
+func useScript(t *testing.T, commands string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.txt")
+	if err := os.WriteFile(path, []byte(commands), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := loadScript(path)
+	if err != nil {
+		t.Fatalf("load the script: %v", err)
+	}
+	old := script
+	script = s
+	t.Cleanup(func() { script = old })
+}
+
+func TestScriptedSession(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, demoSeed)
+	captureUI(t)
+	useScript(t, "# a scripted session\nselect-color Red\nselect-package 30\n"+strings.Repeat("continue\n", 100))
+
+	var out bytes.Buffer
+	interactive(newTextRenderer(&out, false))
+	if !strings.Contains(out.String(), "Event: "+eventLabel(eventLuckyStrike)) {
+		t.Errorf("the session fired no %s:\n%s", eventLabel(eventLuckyStrike), out.String())
+	}
+	if want := "Game ended: " + endToysExhausted.String(); !strings.Contains(out.String(), want) {
+		t.Errorf("the session did not run to completion, want %q:\n%s", want, out.String())
+	}
+	if len(script.commands) == 0 {
+		t.Error("the script ran out of commands before the end of the game")
+	}
+}
+
+func TestLoadScriptUnknownCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.txt")
+	if err := os.WriteFile(path, []byte("select-color Red\n\njump\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadScript(path); err == nil || !strings.Contains(err.Error(), path+":3:") {
+		t.Errorf("load a script with an unknown command: error %v, want one naming line 3", err)
+	}
+}
+
+func TestAutoContinueAfterDryStreak(t *testing.T) {
+	saveEvents(t)
+	// With ten colors and the events of a full or empty board disabled, a turn can fire no event.
+	setFlag(t, "num-colors", "10")
+	setFlag(t, "no-family-event", "true")
+	setFlag(t, "no-clear-event", "true")
+	configureEvents()
+	useSeed(t, 8)
+	captureUI(t)
+	setFlag(t, "auto-continue-after", "1")
+	useScript(t, "select-color Red\nselect-package 30\n"+strings.Repeat("continue\n", 100))
+	var out bytes.Buffer
+	interactive(newTextRenderer(&out, false))
+
+	// The game pauses after each turn until a turn fires no event, then resumes after the next event.
+	turns := strings.Split(out.String(), "========== board ==========")[1:]
+	pauses, dry, resumed := 0, 0, false
+	for _, turn := range turns {
+		if dry++; strings.Contains(turn, "Event: ") {
+			resumed = resumed || dry > 1
+			dry = 0
+		}
+		if dry < 1 {
+			pauses++
+		}
+	}
+	if !resumed {
+		t.Fatal("no event followed a turn without events")
+	}
+	if consumed := 100 - len(script.commands); consumed != pauses {
+		t.Errorf("the game paused %d times over %d turns, want %d", consumed, len(turns), pauses)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// selftest checks the line generator against a brute-force search for board sizes 3 to 6, then exits.
+// It is a diagnostic for the maintainers and is left out of the usage, see hiddenFlags.
+var selftest = flag.Bool("selftest", false, "check the line generator against a brute-force search, then exit")
+
+// hiddenFlags holds the flags left out of the usage.
+var hiddenFlags = map[string]bool{"selftest": true}
+
+// usage function prints the usage of the command, like the default usage of the flag package without hiddenFlags.
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	flag.VisitAll(func(f *flag.Flag) {
+		if hiddenFlags[f.Name] {
+			return
+		}
+		name, usage := flag.UnquoteUsage(f)
+		line := "  -" + f.Name
+		if name != "" {
+			line += " " + name
+		}
+		if len(line) <= 4 {
+			line += "\t"
+		} else {
+			line += "\n    \t"
+		}
+		line += usage
+		switch {
+		case f.DefValue == "" || f.DefValue == "0" || f.DefValue == "false":
+		case name == "string":
+			line += fmt.Sprintf(" (default %q)", f.DefValue)
+		default:
+			line += fmt.Sprintf(" (default %v)", f.DefValue)
+		}
+		_, _ = fmt.Fprintln(out, line)
+	})
+}
+
+// runSelftest function compares the lines of linePlacements with bruteForceLines for board sizes 3 to 6, and exits
+// with a non-zero status on the first mismatch.
+func runSelftest() {
+	for size := 3; size <= 6; size++ {
+		generated := normalizedLines(linePlacements(size, false, true))
+		expected := bruteForceLines(size)
+		if !slices.EqualFunc(generated, expected, slices.Equal[[]int]) {
+			die("selftest: board size %d: generated lines %v, brute force found %v", size, generated, expected)
+		}
+		_, _ = fmt.Fprintf(ui, "selftest: board size %d: %d lines ok\n", size, len(generated))
+	}
+}
+
+// bruteForceLines function returns the lines across a board of size by size slots, found as the sets of size slots
+// lying on a common straight line: for every pair of slots, the slots collinear with them are collected.
+// The lines are normalized as by normalizedLines.
+func bruteForceLines(size int) [][]int {
+	lines := make([][]int, 0)
+	n := size * size
+	for a := range n {
+		for b := a + 1; b < n; b++ {
+			line := make([]int, 0, size)
+			for c := range n {
+				if collinear(a, b, c, size) {
+					line = append(line, c)
+				}
+			}
+			if len(line) == size {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return normalizedLines(lines)
+}
+
+// collinear function reports whether the slots a, b and c of a board of size by size slots lie on a straight line.
+func collinear(a, b, c, size int) bool {
+	ar, ac := a/size, a%size
+	br, bc := b/size, b%size
+	cr, cc := c/size, c%size
+	return (br-ar)*(cc-ac) == (bc-ac)*(cr-ar)
+}
+
+// normalizedLines function returns the distinct lines, each sorted in ascending order of slots, in ascending order.
+func normalizedLines(lines [][]int) [][]int {
+	normalized := make([][]int, 0, len(lines))
+	for _, line := range lines {
+		normalized = append(normalized, sortedSlots(line))
+	}
+	slices.SortFunc(normalized, slices.Compare[[]int])
+	return slices.CompactFunc(normalized, slices.Equal[[]int])
+}
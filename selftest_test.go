@@ -0,0 +1,20 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLineGenerator(t *testing.T) {
+	for size := 3; size <= 6; size++ {
+		generated := normalizedLines(linePlacements(size, false, true))
+		expected := bruteForceLines(size)
+		if !slices.EqualFunc(generated, expected, slices.Equal[[]int]) {
+			t.Errorf("board size %d: generated lines %v, brute force found %v", size, generated, expected)
+		}
+		// The rows, the columns and the two diagonals.
+		if want := 2*size + 2; len(generated) != want {
+			t.Errorf("board size %d: %d lines, want %d", size, len(generated), want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// shapesFile loads custom shapes, each declared on its own line as "Name | cells | points":
+//
+//	# Blank lines and lines starting with # are ignored.
+//	Corner | 0,0 1,0 2,0 2,1 | 4
+//	Tee | 0,0 0,1 0,2 1,1 2,1 | 6
+//
+// The cells are "row,column" offsets relative to an anchor, neither of which is negative. A shape fires when toys of
+// a single color fill the cells of any placement of the shape on the board: translated anywhere it fits and rotated by
+// quarter turns. Custom shapes are resolved like the built-in lines, see checkBoard: their slots are cleared and their
+// toys are acquired.
+var shapesFile = flag.String("shapes-file", "", "load custom shapes from this file")
+
+// toroidal treats the board as a torus for the lines: a line leaving an edge of the board comes back on the opposite
+// edge. On the 3x3 board the rows and the columns already span the board, so this adds the four broken diagonals,
+// slots 1, 5, 6 and 2, 3, 7 parallel to the diagonal 0, 4, 8, and slots 0, 5, 7 and 1, 3, 8 parallel to the
+// diagonal 2, 4, 6. The custom shapes of -shapes-file do not wrap.
+var toroidal = flag.Bool("toroidal", false, "let the lines wrap around the edges of the board")
+
+// noDiagonals leaves the diagonals out of the lines, as on the machines that only reward rows and columns. Under
+// -toroidal, the broken diagonals are left out as well.
+var noDiagonals = flag.Bool("no-diagonals", false, "only count rows and columns as lines, not diagonals")
+
+// cornersPoints enables the Corners event, which fires when the corner slots of the board all hold toys of the same
+// color, and sets its bonus. Corners is resolved like the custom shapes: the corner slots are cleared and their toys
+// acquired. Zero disables the event.
+var cornersPoints = flag.Int("corners", 0, "bonus of the Corners event, when the corners hold the same color (0 to disable)")
+
+// cell is the offset of a slot of a pattern, relative to the anchor of the pattern.
+type cell struct {
+	row, col int
+}
+
+// shape is a pattern that fires its event when toys of a single color fill the slots of any of its placements.
+type shape struct {
+	event      int
+	placements [][]int
+}
+
+// shapes holds the shapes resolved by checkBoard: the built-in lines, which fire Lucky Strike, then the custom shapes
+// of -shapes-file.
+var shapes = []shape{{eventLuckyStrike, tripleCombination}}
+
+// linePatterns function returns the patterns of the lines across a board of size by size slots: a row and a diagonal,
+// whose rotations give the columns and the other diagonal.
+func linePatterns(size int) [][]cell {
+	row, diagonal := make([]cell, size), make([]cell, size)
+	for k := range size {
+		row[k], diagonal[k] = cell{0, k}, cell{k, k}
+	}
+	return [][]cell{row, diagonal}
+}
+
+// linePlacements function returns the slots of every line across a board of size by size slots.
+// With wrap set, the lines also wrap around the edges of the board, see toroidal; with diagonals unset, only the rows
+// and the columns are lines.
+func linePlacements(size int, wrap, diagonals bool) [][]int {
+	lines := make([][]int, 0)
+	patterns := linePatterns(size)
+	if !diagonals {
+		patterns = patterns[:1]
+	}
+	for _, p := range patterns {
+		lines = append(lines, placements(p, size, wrap)...)
+	}
+	return lines
+}
+
+// placements function returns the slots of every distinct placement of pattern on a board of size by size slots,
+// translated and rotated by quarter turns. The slots of a placement are in the order of the cells of the pattern.
+// With wrap set, the placements also wrap around the edges of the board, as on a torus.
+func placements(pattern []cell, size int, wrap bool) [][]int {
+	all := make([][]int, 0)
+	seen := make(map[string]bool)
+	for range 4 {
+		height, width := 0, 0
+		for _, c := range pattern {
+			height, width = max(height, c.row+1), max(width, c.col+1)
+		}
+		rows, cols := size-height+1, size-width+1
+		if wrap && height <= size && width <= size {
+			rows, cols = size, size
+		}
+		for dr := 0; dr < rows; dr++ {
+			for dc := 0; dc < cols; dc++ {
+				slots := make([]int, len(pattern))
+				for k, c := range pattern {
+					slots[k] = (c.row+dr)%size*size + (c.col+dc)%size
+				}
+				key := fmt.Sprint(sortedSlots(slots))
+				if !seen[key] {
+					seen[key] = true
+					all = append(all, slots)
+				}
+			}
+		}
+		pattern = rotate(pattern)
+	}
+	return all
+}
+
+// sortedSlots function returns a copy of slots in ascending order.
+func sortedSlots(slots []int) []int {
+	sorted := slices.Clone(slots)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// rotate function returns pattern rotated by a quarter turn, moved so that its smallest row and column are 0.
+func rotate(pattern []cell) []cell {
+	rotated := make([]cell, len(pattern))
+	minRow, minCol := 0, 0
+	for k, c := range pattern {
+		rotated[k] = cell{c.col, -c.row}
+		minRow, minCol = min(minRow, rotated[k].row), min(minCol, rotated[k].col)
+	}
+	for k := range rotated {
+		rotated[k].row -= minRow
+		rotated[k].col -= minCol
+	}
+	return rotated
+}
+
+// loadShapes function reads the custom shapes of the file at path and registers their events next to the built-in events.
+func loadShapes(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "|")
+		if len(fields) != 3 {
+			return fmt.Errorf("%s:%d: want \"Name | cells | points\", got %q", path, line, text)
+		}
+		pattern, err := parseCells(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		points, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil || points < 0 {
+			return fmt.Errorf("%s:%d: invalid points %q", path, line, strings.TrimSpace(fields[2]))
+		}
+		slots := placements(pattern, boardSize, false)
+		if len(slots) == 0 {
+			return fmt.Errorf("%s:%d: the shape does not fit on the board", path, line)
+		}
+		e := registerEvent(strings.TrimSpace(fields[0]),
+			"toys of the same color fill the shape, they are collected and %d more toys are placed", points)
+		eventAcquired[e] = len(pattern)
+		shapes = append(shapes, shape{e, slots})
+	}
+	return sc.Err()
+}
+
+// parseCells function parses the space-separated "row,column" cells of a shape, whose offsets are not negative.
+func parseCells(s string) ([]cell, error) {
+	pattern := make([]cell, 0)
+	for _, field := range strings.Fields(s) {
+		r, c, ok := strings.Cut(field, ",")
+		row, errRow := strconv.Atoi(r)
+		col, errCol := strconv.Atoi(c)
+		if !ok || errRow != nil || errCol != nil {
+			return nil, fmt.Errorf("invalid cell %q, want row,column", field)
+		}
+		if row < 0 || col < 0 {
+			return nil, fmt.Errorf("invalid cell %q, want a row and a column that are not negative", field)
+		}
+		if slices.Contains(pattern, cell{row, col}) {
+			return nil, fmt.Errorf("duplicate cell %q", field)
+		}
+		pattern = append(pattern, cell{row, col})
+	}
+	if len(pattern) == 0 {
+		return nil, fmt.Errorf("a shape needs at least one cell")
+	}
+	return pattern, nil
+}
+
+// cornerSlots function returns the corner slots of a board of size by size slots.
+func cornerSlots(size int) []int {
+	return []int{0, size - 1, size * (size - 1), size*size - 1}
+}
+
+// registerCorners function registers the Corners event and its shape, see cornersPoints.
+func registerCorners() {
+	e := registerEvent("Corners",
+		"the corners hold toys of the same color, they are collected and %d more toys are placed", *cornersPoints)
+	corners := cornerSlots(boardSize)
+	eventAcquired[e] = len(corners)
+	shapes = append(shapes, shape{e, [][]int{corners}})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeShapes function writes the shapes file content to a temporary file and returns its path.
+func writeShapes(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shapes.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCustomShapeL(t *testing.T) {
+	saveEvents(t)
+	if err := loadShapes(writeShapes(t, "Small L | 0,0 1,0 1,1 | 3\n")); err != nil {
+		t.Fatalf("load the shapes: %v", err)
+	}
+	smallL := shapes[len(shapes)-1].event
+	for _, tc := range []struct {
+		name  string
+		board []int
+		slots []int
+	}{
+		{"as declared", []int{1, 2, 3, 4, 5, 6, 7, 5, 5}, []int{4, 7, 8}},
+		{"rotated", []int{5, 5, 1, 5, 2, 3, 4, 6, 7}, []int{0, 1, 3}},
+	} {
+		board := slices.Clone(tc.board)
+		events, _, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+		if want := []int{smallL}; !slices.Equal(eventTypes(events), want) {
+			t.Errorf("%s: events = %v, want %v", tc.name, eventTypes(events), want)
+			continue
+		}
+		if events[0].acquired[5] != 3 {
+			t.Errorf("%s: Small L acquired %v, want 3 Green", tc.name, events[0].acquired)
+		}
+		for _, k := range tc.slots {
+			if board[k] != 0 {
+				t.Errorf("%s: slot %d holds %d, want it cleared", tc.name, k, board[k])
+			}
+		}
+	}
+}
+
+func TestLoadShapesNegativeOffset(t *testing.T) {
+	saveEvents(t)
+	for _, cells := range []string{"0,0 -1,0 1,0", "0,-1 0,0"} {
+		err := loadShapes(writeShapes(t, "Bad | "+cells+" | 3\n"))
+		if err == nil || !strings.Contains(err.Error(), "not negative") {
+			t.Errorf("load a shape of cells %q: error %v, want one rejecting the negative offset", cells, err)
+		}
+	}
+}
+
+func TestLinePlacementsToroidal(t *testing.T) {
+	flat := normalizedLines(linePlacements(3, false, true))
+	wrapped := normalizedLines(linePlacements(3, true, true))
+	want := normalizedLines(append(slices.Clone(flat), []int{1, 5, 6}, []int{2, 3, 7}, []int{0, 5, 7}, []int{1, 3, 8}))
+	if !slices.EqualFunc(wrapped, want, slices.Equal[[]int]) {
+		t.Errorf("toroidal lines = %v, want the %d lines plus the broken diagonals, %v", wrapped, len(flat), want)
+	}
+	if rows := normalizedLines(linePlacements(3, true, false)); len(rows) != 6 {
+		t.Errorf("toroidal lines without diagonals = %v, want the rows and the columns", rows)
+	}
+}
+
+func TestCornersEvent(t *testing.T) {
+	saveEvents(t)
+	setFlag(t, "corners", "5")
+	registerCorners()
+	corners := shapes[len(shapes)-1].event
+	board := []int{6, 1, 6, 2, 3, 4, 6, 5, 6}
+	events, _, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if want := []int{corners}; !slices.Equal(eventTypes(events), want) {
+		t.Fatalf("events = %v, want %v", eventTypes(events), want)
+	}
+	if events[0].acquired[6] != 4 || eventRewardRules[corners] != 5 {
+		t.Errorf("Corners acquired %v for %d points, want 4 Cyan for 5", events[0].acquired, eventRewardRules[corners])
+	}
+	if want := []int{0, 1, 0, 2, 3, 4, 0, 5, 0}; !slices.Equal(board, want) {
+		t.Errorf("board = %v, want the corners cleared, %v", board, want)
+	}
+	if got := cornerSlots(5); !slices.Equal(got, []int{0, 4, 20, 24}) {
+		t.Errorf("corners of the 5x5 board = %v, want [0 4 20 24]", got)
+	}
+}
+
+func TestNoDiagonals(t *testing.T) {
+	saveEvents(t)
+	for _, diagonals := range []bool{true, false} {
+		tripleCombination = linePlacements(boardSize, false, diagonals)
+		shapes[0].placements = tripleCombination
+		board := []int{3, 1, 2, 4, 3, 5, 6, 7, 3}
+		events, _, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+		if got := slices.Contains(eventTypes(events), eventLuckyStrike); got != diagonals {
+			t.Errorf("with diagonals %v, Lucky Strike fired on the diagonal: %v, want %v", diagonals, got, diagonals)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// share prints a compact result at the end of the game, meant to be copied and pasted: the seed, the configuration,
+// the final board as a grid of emoji and the outcome. Replaying the printed flags with the same lucky color and
+// package reproduces the game.
+var share = flag.Bool("share", false, "print a shareable result at the end of the game")
+
+// colorEmoji holds the emoji of each color in the shareable result, indexed by color (0-based), and emptyEmoji the
+// emoji of an empty slot.
+var (
+	colorEmoji = []string{"❤️", "💛", "💜", "🧡", "💚", "🩵", "🩷", "💙", "🤎", "💗"}
+	emptyEmoji = "🤍"
+)
+
+// shareText function returns the shareable result of the game with the outcome res and the final board.
+func shareText(res gameResult, board []int) string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "Lucky Match, seed %d\n", *seed)
+	_, _ = fmt.Fprintf(&b, "Lucky %s, %d toys package\n", res.LuckyColor, res.Package)
+	flags := make([]string, 0)
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name != "seed" && f.Name != "share" && !manifestExcluded[f.Name] {
+			flags = append(flags, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+		}
+	})
+	if len(flags) > 0 {
+		_, _ = fmt.Fprintf(&b, "Flags: %s\n", strings.Join(flags, " "))
+	}
+	for i, v := range board {
+		if v > 0 {
+			b.WriteString(colorEmoji[v-1])
+		} else {
+			b.WriteString(emptyEmoji)
+		}
+		if i%boardSize == boardSize-1 {
+			b.WriteString("\n")
+		}
+	}
+	_, _ = fmt.Fprintf(&b, "%d toys, %d points\n", res.Total, res.Score)
+	return b.String()
+}
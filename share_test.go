@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestShareText(t *testing.T) {
+	isolateFlags(t)
+	useSeed(t, 1234)
+	res := gameResult{LuckyColor: "Red", Package: 18, Total: 25, Score: 16}
+	board := []int{1, 0, 0, 0, 8, 0, 0, 0, 2}
+	want := "Lucky Match, seed 1234\n" +
+		"Lucky Red, 18 toys package\n" +
+		"❤️🤍🤍\n" +
+		"🤍💙🤍\n" +
+		"🤍🤍💛\n" +
+		"25 toys, 16 points\n"
+	if got := shareText(res, board); got != want {
+		t.Errorf("share text =\n%s\nwant\n%s", got, want)
+	}
+	// The flags given on the command line follow the package, so that the game can be reconstructed.
+	if err := flag.Set("pair-greedy", "false"); err != nil {
+		t.Fatal(err)
+	}
+	want = strings.Replace(want, "package\n", "package\nFlags: -pair-greedy=false\n", 1)
+	if got := shareText(res, board); got != want {
+		t.Errorf("share text =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// toyValue and packageCost turn the outcome of a game into a monetary view: the value of the acquired toys, the cost of
+// the package and the net value. The report is off unless one of them is set. packageCost is either a single cost for
+// every package or a list of "size=cost" entries, e.g. "9=20,18=36,30=55". Both are checked at startup, see
+// initPackageCosts, and a package missing from the list is reported as soon as it is selected, see checkPackageCost.
+var (
+	toyValue    = flag.Float64("toy-value", 0, "value of one toy, for the spend report")
+	packageCost = flag.String("package-cost", "", "cost of the package, as a single cost or a list of size=cost entries")
+)
+
+// packageCosts holds the cost of each package size of -package-cost, or nil without -package-cost.
+var packageCosts map[int]float64
+
+// spendReport is the monetary view of the outcome of a game.
+type spendReport struct {
+	ToyValue    float64 `json:"toy_value"`
+	PackageCost float64 `json:"package_cost"`
+	Net         float64 `json:"net"`
+}
+
+// validAmount function reports whether v is a finite amount that is not negative.
+func validAmount(v float64) bool {
+	return v >= 0 && !math.IsInf(v, 0)
+}
+
+// initPackageCosts function parses the costs of -package-cost.
+func initPackageCosts() error {
+	costs, err := parsePackageCosts(*packageCost)
+	packageCosts = costs
+	return err
+}
+
+// parsePackageCosts function parses the costs of spec, see packageCost, keyed by package size. A single cost applies
+// to every entry of packages.
+func parsePackageCosts(spec string) (map[int]float64, error) {
+	costs := make(map[int]float64)
+	if !strings.Contains(spec, "=") {
+		cost, err := strconv.ParseFloat(strings.TrimSpace(spec), 64)
+		if err != nil || !validAmount(cost) {
+			return nil, fmt.Errorf("invalid cost %q", spec)
+		}
+		for _, n := range packages {
+			costs[n] = cost
+		}
+		return costs, nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		size, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid package cost %q, want size=cost", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid package size %q", size)
+		}
+		cost, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || !validAmount(cost) {
+			return nil, fmt.Errorf("invalid cost %q", value)
+		}
+		costs[n] = cost
+	}
+	return costs, nil
+}
+
+// checkPackageCost function fails when -package-cost lists no cost for the package of packageSize toys. It runs once
+// the package is selected, so that the game is not played for a spend report that cannot be made.
+func checkPackageCost(packageSize int) error {
+	if _, ok := packageCosts[packageSize]; packageCosts != nil && !ok {
+		return fmt.Errorf("no cost for the package of %d toys in %q", packageSize, *packageCost)
+	}
+	return nil
+}
+
+// newSpendReport function returns the spend report of a game with total toys from a package of packageSize toys,
+// or nil when the report is off.
+func newSpendReport(packageSize, total int) (*spendReport, error) {
+	if *toyValue == 0 && packageCosts == nil {
+		return nil, nil
+	}
+	if err := checkPackageCost(packageSize); err != nil {
+		return nil, err
+	}
+	value := float64(total) * *toyValue
+	cost := packageCosts[packageSize]
+	return &spendReport{ToyValue: value, PackageCost: cost, Net: value - cost}, nil
+}
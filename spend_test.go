@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// usePackageCosts function sets -package-cost to spec for the duration of the test.
+func usePackageCosts(t *testing.T, spec string) {
+	t.Helper()
+	old := packageCosts
+	t.Cleanup(func() { packageCosts = old })
+	setFlag(t, "package-cost", spec)
+	if err := initPackageCosts(); err != nil {
+		t.Fatalf("-package-cost=%s: %v", spec, err)
+	}
+}
+
+func TestSpendReportNet(t *testing.T) {
+	useSeed(t, 2)
+	setFlag(t, "toy-value", "1.5")
+	usePackageCosts(t, "9=20,18=36,30=55")
+	res := playGame(newGame(3, 18), discardRenderer{})
+	if res.Spend == nil {
+		t.Fatal("the outcome holds no spend report")
+	}
+	if want := float64(res.Total)*1.5 - 36; math.Abs(res.Spend.Net-want) > 1e-9 {
+		t.Errorf("net = %v for %d toys, want %v", res.Spend.Net, res.Total, want)
+	}
+}
+
+func TestParsePackageCostsInvalid(t *testing.T) {
+	for _, spec := range []string{"-5", "NaN", "+Inf", "9=20,18=-1", "9=NaN", "0=20", "9:20"} {
+		if _, err := parsePackageCosts(spec); err == nil {
+			t.Errorf("-package-cost=%s parsed, want an error", spec)
+		}
+	}
+	if validAmount(math.NaN()) || validAmount(-1) {
+		t.Error("a NaN or negative -toy-value is valid, want it rejected")
+	}
+}
+
+func TestCheckPackageCostUncovered(t *testing.T) {
+	usePackageCosts(t, "9=20,30=55")
+	if err := checkPackageCost(30); err != nil {
+		t.Errorf("check the cost of the package of 30 toys: %v", err)
+	}
+	if err := checkPackageCost(18); err == nil {
+		t.Error("checked the cost of the package of 18 toys, missing from the list, want an error")
+	}
+}
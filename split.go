@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// split plays several games side by side, in lockstep, each with its own lucky color but all with the same draws:
+// the n-th toy placed in every game has the same color, and the wild draws of -wild-chance are rolled alike. The shared
+// draws ignore -lucky-boost, as the games do not share a lucky color.
+var split = flag.Int("split", 0, "play this many games side by side, with the same draws and different lucky colors")
+
+// sharedDraws is the sequence of colors (1-based) drawn for games played side by side, and of the rolls of their wild
+// draws.
+type sharedDraws struct {
+	colors []int
+	rolls  []float64
+}
+
+// at method returns the n-th color (0-based) of the sequence, drawing it and the ones before it on first use.
+func (s *sharedDraws) at(n int) int {
+	for len(s.colors) <= n {
+		s.colors = append(s.colors, drawColor(0))
+	}
+	return s.colors[n]
+}
+
+// roll method returns the n-th roll (0-based) of the wild draws of -wild-chance, rolling it and the ones before it on
+// first use.
+func (s *sharedDraws) roll(n int) float64 {
+	for len(s.rolls) <= n {
+		s.rolls = append(s.rolls, rng.Float64())
+	}
+	return s.rolls[n]
+}
+
+// depleted method reports whether the sequence has no n-th color (0-based) under -finite-stock: the color was drawn
+// after the stock ran out, or is still to be drawn from a depleted stock. A game behind the others thus plays the
+// colors already drawn for them, even once the stock is empty.
+func (s *sharedDraws) depleted(n int) bool {
+	if n < len(s.colors) {
+		return s.colors[n] == 0
+	}
+	return stockDepleted()
+}
+
+// columnRenderer renders a game as a column of lines, to be printed next to the columns of the other games.
+// The board and the events are rendered by the embedded TextRenderer, with compact events.
+type columnRenderer struct {
+	*TextRenderer
+	buf *bytes.Buffer
+}
+
+// newColumnRenderer function returns a columnRenderer whose column starts with title.
+func newColumnRenderer(title string) *columnRenderer {
+	buf := bytes.NewBufferString(title + "\n")
+	return &columnRenderer{newTextRenderer(buf, true), buf}
+}
+
+// Acquired method prints the number of toys acquired so far, the number of toys that remain to be placed and,
+// unless -quiet is set, the points scored so far.
+func (r *columnRenderer) Acquired(acq []int, remaining, score int) {
+	if *quiet {
+		_, _ = fmt.Fprintf(r.w, "Toys: %d; Remaining: %d\n", total(acq), remaining)
+		return
+	}
+	_, _ = fmt.Fprintf(r.w, "Toys: %d; Remaining: %d; Score: %d\n", total(acq), remaining, score)
+}
+
+// Summary method prints the total number of acquired toys.
+func (r *columnRenderer) Summary(res gameResult) {
+	_, _ = fmt.Fprintf(r.w, "You have received %d toys\n", res.Total)
+}
+
+// lines method returns the lines of the column.
+func (r *columnRenderer) lines() []string {
+	return strings.Split(strings.TrimRight(r.buf.String(), "\n"), "\n")
+}
+
+// total function returns the number of toys in acq.
+func total(acq []int) int {
+	n := 0
+	for _, v := range acq {
+		n += v
+	}
+	return n
+}
+
+// playSplit function runs the games of -split: it selects a distinct lucky color for each game and a shared package,
+// then plays all games one turn at a time, printing their columns side by side, and finally prints each total.
+func playSplit() {
+	printPaletteWarnings()
+	startGame()
+	luckyColors := make([]int, 0, *split)
+	for len(luckyColors) < *split {
+		_, _ = fmt.Fprintf(ui, "Game %d\n", len(luckyColors)+1)
+		free := 1
+		for slices.Contains(luckyColors, free) {
+			free++
+		}
+		c := selectLuckColor(free)
+		if slices.Contains(luckyColors, c) {
+			_, _ = fmt.Fprintf(ui, "%s is the lucky color of another game, please choose another one\n", colors[c-1])
+			continue
+		}
+		luckyColors = append(luckyColors, c)
+	}
+	packageSize := selectPackageType()
+	if err := checkPackageCost(packageSize); err != nil {
+		die("-package-cost is invalid, %v", err)
+	}
+	applyPackageRewards(packageSize)
+	applyMultiplierCard()
+	games := newSplitGames(luckyColors, packageSize)
+	defer logPanic(func() int {
+		return slices.MaxFunc(games, func(a, b *game) int { return a.turn - b.turn }).turn
+	})
+	for slices.ContainsFunc(games, func(g *game) bool { return !g.over() }) {
+		columns := make([][]string, len(games))
+		for i, g := range games {
+			r := newColumnRenderer(splitTitle(i, g))
+			if !g.over() {
+				g.playTurn(r)
+			} else {
+				_, _ = fmt.Fprintln(r.w, "No toy remaining")
+			}
+			columns[i] = r.lines()
+		}
+		printColumns(columns)
+		if !next() {
+			for _, g := range games {
+				g.cancelled = true
+			}
+		}
+	}
+	columns := make([][]string, len(games))
+	for i, g := range games {
+		r := newColumnRenderer(splitTitle(i, g))
+		r.Summary(g.finish())
+		columns[i] = r.lines()
+	}
+	printColumns(columns)
+}
+
+// newSplitGames function returns a game for each of luckyColors, all with the package packageSize, the same starter
+// slots and the same draws.
+func newSplitGames(luckyColors []int, packageSize int) []*game {
+	shared := &sharedDraws{}
+	games := make([]*game, len(luckyColors))
+	var starter []int
+	for i, c := range luckyColors {
+		g := newGame(c, packageSize)
+		if i == 0 {
+			starter = starterSlots(g.board)
+		}
+		g.boostStarter(starter)
+		drawn := 0
+		g.draw = func() int {
+			drawn++
+			return shared.at(drawn - 1)
+		}
+		g.depleted = func() bool { return shared.depleted(drawn) }
+		rolled := 0
+		g.roll = func() float64 {
+			rolled++
+			return shared.roll(rolled - 1)
+		}
+		games[i] = g
+	}
+	return games
+}
+
+// splitTitle function returns the title of the column of the i-th game (0-based).
+func splitTitle(i int, g *game) string {
+	return fmt.Sprintf("Game %d (lucky %s)", i+1, colors[g.luckyColor-1])
+}
+
+// printColumns function prints columns of lines side by side, each padded to its longest line.
+func printColumns(columns [][]string) {
+	widths := make([]int, len(columns))
+	rows := 0
+	for i, col := range columns {
+		for _, line := range col {
+			widths[i] = max(widths[i], utf8.RuneCountInString(line))
+		}
+		rows = max(rows, len(col))
+	}
+	for row := 0; row < rows; row++ {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			line := ""
+			if row < len(col) {
+				line = col[row]
+			}
+			cells[i] = fmt.Sprintf("%-*s", widths[i], line)
+		}
+		_, _ = fmt.Fprintln(ui, strings.TrimRight(strings.Join(cells, " | "), " "))
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordDraws function makes g record the colors it draws into the returned slice.
+func recordDraws(g *game) *[]int {
+	drawn := make([]int, 0)
+	draw := g.draw
+	g.draw = func() int {
+		c := draw()
+		drawn = append(drawn, c)
+		return c
+	}
+	return &drawn
+}
+
+// playLockstep function plays games one turn at a time until they are all over.
+func playLockstep(games []*game) {
+	for slices.ContainsFunc(games, func(g *game) bool { return !g.over() }) {
+		for _, g := range games {
+			if !g.over() {
+				g.playTurn(discardRenderer{})
+			}
+		}
+	}
+}
+
+func TestSplitGamesShareDraws(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, 5)
+	games := newSplitGames([]int{1, 8}, 30)
+	first, second := recordDraws(games[0]), recordDraws(games[1])
+	playLockstep(games)
+	n := min(len(*first), len(*second))
+	if n == 0 {
+		t.Fatal("the games drew no toy")
+	}
+	if !slices.Equal((*first)[:n], (*second)[:n]) {
+		t.Errorf("the games drew different sequences:\n%v\n%v", *first, *second)
+	}
+}
+
+func TestSplitGamesDepleteStockPerGame(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, 5)
+	useStock(t, "1")
+	games := newSplitGames([]int{1, 8}, 30)
+	first, second := recordDraws(games[0]), recordDraws(games[1])
+	// The first game empties the stock before the second one starts: the second game still plays the same toys.
+	playLockstep(games[:1])
+	playLockstep(games[1:])
+	if len(*first) != *numColors || !slices.Equal(*first, *second) {
+		t.Errorf("the games drew\n%v\n%v\nwant the same %d toys of the stock", *first, *second, *numColors)
+	}
+}
+
+func TestPlaySplitPaletteWarnings(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, 5)
+	setFlag(t, "num-colors", "3")
+	setFlag(t, "split", "2")
+	out := captureUI(t)
+	useScript(t, "select-color Red\nselect-color Yellow\nselect-package 9\nquit\n")
+	playSplit()
+	if !strings.Contains(out.String(), "Warning: only 3 colors are in play") {
+		t.Errorf("the split games print no palette warning:\n%s", out)
+	}
+}
+
+func TestPlaySplitLogsSeedOnPanic(t *testing.T) {
+	saveEvents(t)
+	useSeed(t, 5)
+	setFlag(t, "split", "2")
+	captureUI(t)
+	useScript(t, "select-color Red\nselect-color Yellow\nselect-package 9\n"+strings.Repeat("continue\n", 20))
+	var logs bytes.Buffer
+	old := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(old) })
+	customEvents = []customEvent{{eventOnePair, func([]int) (bool, int) { panic("injected") }}}
+
+	defer func() {
+		if p := recover(); p != "injected" {
+			t.Fatalf("recovered %v, want the injected panic to propagate", p)
+		}
+		if out := logs.String(); !strings.Contains(out, "seed=5") || !strings.Contains(out, "turn=1") {
+			t.Errorf("the log leaves out the seed or the turn of the panic:\n%s", out)
+		}
+	}()
+	playSplit()
+}
+
+func TestSplitGamesShareWildRolls(t *testing.T) {
+	saveEvents(t)
+	registerWildDraw()
+	setFlag(t, "wild-chance", "0.5")
+	useSeed(t, 5)
+	games := newSplitGames([]int{1, 8}, 30)
+	rolls := make([][]float64, len(games))
+	for i, g := range games {
+		roll := g.roll
+		g.roll = func() float64 {
+			r := roll()
+			rolls[i] = append(rolls[i], r)
+			return r
+		}
+	}
+	playLockstep(games)
+	n := min(len(rolls[0]), len(rolls[1]))
+	if n == 0 {
+		t.Fatal("the games rolled no wild draw")
+	}
+	if !slices.Equal(rolls[0][:n], rolls[1][:n]) {
+		t.Errorf("the games rolled different wild draws:\n%v\n%v", rolls[0][:n], rolls[1][:n])
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import "flag"
+
+// starterBoost places two toys of the lucky color in a line of the board at the start of the game, so that the player
+// starts one toy away from a Lucky Strike. The line and its two slots are picked with the generator of the game among
+// the lines with two empty slots or more, e.g. on a board of -board-file. The toys are a gift: they are not drawn from
+// the package nor from the stock of -finite-stock. The games of -split share the slots, each with its own lucky color.
+// See starterSlots.
+var starterBoost = flag.Bool("starter-boost", false, "start the game with two toys of the lucky color in a line")
+
+// starterSlots function picks, with rng, two empty slots of a line of board under -starter-boost. It returns nil
+// without -starter-boost, or when no line has room for two toys.
+func starterSlots(board []int) []int {
+	if !*starterBoost {
+		return nil
+	}
+	candidates := make([][]int, 0)
+	for _, line := range tripleCombination {
+		empty := make([]int, 0, len(line))
+		for _, k := range line {
+			if board[k] == 0 {
+				empty = append(empty, k)
+			}
+		}
+		if len(empty) >= 2 {
+			candidates = append(candidates, empty)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	empty := candidates[rng.IntN(len(candidates))]
+	rng.Shuffle(len(empty), func(i, j int) { empty[i], empty[j] = empty[j], empty[i] })
+	return empty[:2]
+}
+
+// boostStarter method places a toy of the lucky color in each of slots, picked by starterSlots, before the first turn.
+func (g *game) boostStarter(slots []int) {
+	for _, k := range slots {
+		g.board[k] = g.luckyColor
+	}
+	g.orderedEmptySlots = emptySlots(g.board)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStarterBoost(t *testing.T) {
+	setFlag(t, "starter-boost", "true")
+	for s := range uint64(20) {
+		useSeed(t, s)
+		g := newGame(4, 30)
+		g.boostStarter(starterSlots(g.board))
+		slots := make([]int, 0)
+		for k, v := range g.board {
+			if v != 0 {
+				slots = append(slots, k)
+			}
+			if v != 0 && v != 4 {
+				t.Fatalf("seed %d: board = %v, want only toys of the lucky color", s, g.board)
+			}
+		}
+		if len(slots) != 2 {
+			t.Fatalf("seed %d: board = %v, want exactly 2 toys", s, g.board)
+		}
+		inLine := slices.ContainsFunc(tripleCombination, func(line []int) bool {
+			return slices.Contains(line, slots[0]) && slices.Contains(line, slots[1])
+		})
+		if !inLine {
+			t.Errorf("seed %d: the toys of slots %v share no line", s, slots)
+		}
+		if slices.Contains(g.orderedEmptySlots, slots[0]) || slices.Contains(g.orderedEmptySlots, slots[1]) {
+			t.Errorf("seed %d: empty slots %v still hold the boosted slots %v", s, g.orderedEmptySlots, slots)
+		}
+	}
+}
+
+func TestSplitGamesShareStarter(t *testing.T) {
+	setFlag(t, "starter-boost", "true")
+	useSeed(t, 3)
+	games := newSplitGames([]int{1, 2}, 30)
+	for k := range games[0].board {
+		if (games[0].board[k] == 0) != (games[1].board[k] == 0) {
+			t.Fatalf("boards %v and %v, want the same starter slots", games[0].board, games[1].board)
+		}
+	}
+	if slices.Max(games[0].board) != 1 || slices.Max(games[1].board) != 2 {
+		t.Errorf("boards %v and %v, want each boosted with its own lucky color", games[0].board, games[1].board)
+	}
+}
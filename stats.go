@@ -0,0 +1,158 @@
+package main
+
+// gameStats collects the metrics tracked across the turns of a game and reported in the final summary.
+type gameStats struct {
+	// peakDistinct is the highest number of distinct colors that were on the board at the same time.
+	peakDistinct int
+	// dryStreak is the number of consecutive turns without any event up to the current turn,
+	// and longestDryStreak the longest such run over the game.
+	dryStreak        int
+	longestDryStreak int
+	// turns holds the record of each turn played, in order.
+	turns []turnRecord
+	// maxPairs is the highest number of pairs resolved in a single turn.
+	maxPairs int
+	// events and placements count the events reported and the toys placed over the game.
+	events     int
+	placements int
+	// overlaps and maxOverlaps count the slots that were part of several completed lines or shapes at once, over the
+	// game and in a single turn.
+	overlaps    int
+	maxOverlaps int
+	// maxFreed is the highest number of slots freed by the matches of a single turn, and portraits and clears count
+	// the Family Portrait and Clear The Board events, for the closest approach to those that never fired.
+	maxFreed  int
+	portraits int
+	clears    int
+}
+
+// observePlacement method updates the metrics that depend on the board right after the toys of a turn were placed,
+// before any combination is resolved.
+func (s *gameStats) observePlacement(board []int) {
+	s.peakDistinct = max(s.peakDistinct, distinctColors(board))
+}
+
+// observeOverlaps method updates the metrics that depend on the slots part of several completed lines or shapes,
+// counted before they are resolved.
+func (s *gameStats) observeOverlaps(n int) {
+	s.overlaps += n
+	s.maxOverlaps = max(s.maxOverlaps, n)
+}
+
+// observeFreed method updates the metrics that depend on the number of slots freed by the matches of a turn.
+func (s *gameStats) observeFreed(freed int) {
+	s.maxFreed = max(s.maxFreed, freed)
+}
+
+// closestApproach method returns, for the rare events that never fired over the game, how close the game came to
+// them, keyed by event name: the most distinct colors on the board for Family Portrait, and the most slots freed in
+// a turn for Clear The Board.
+func (s *gameStats) closestApproach() map[string]int {
+	closest := make(map[string]int)
+	if s.portraits == 0 && !disabledEvents[eventAllDifferent] {
+		closest[eventNames[eventAllDifferent]] = s.peakDistinct
+	}
+	if s.clears == 0 && !disabledEvents[eventClear] {
+		closest[eventNames[eventClear]] = s.maxFreed
+	}
+	return closest
+}
+
+// observeTurn method updates the metrics that depend on the events reported for a turn and the toys placed during it.
+func (s *gameStats) observeTurn(events []ev, placed int) {
+	s.events += len(events)
+	s.placements += placed
+	pairs := 0
+	for _, e := range events {
+		switch e.event {
+		case eventOnePair:
+			pairs++
+		case eventAllDifferent:
+			s.portraits++
+		case eventClear:
+			s.clears++
+		}
+	}
+	s.maxPairs = max(s.maxPairs, pairs)
+	if len(events) > 0 {
+		s.dryStreak = 0
+		return
+	}
+	s.dryStreak++
+	s.longestDryStreak = max(s.longestDryStreak, s.dryStreak)
+}
+
+// efficiency method returns the number of events reported per toy placed over the game, or 0 before any placement.
+func (s *gameStats) efficiency() float64 {
+	if s.placements == 0 {
+		return 0
+	}
+	return float64(s.events) / float64(s.placements)
+}
+
+// overlappingSlots function returns the number of slots of the board that are part of more than one completed line
+// or shape, before checkBoard resolves them. Pairs are left out: they are only formed from the toys that no line nor
+// shape collected, and so never overlap.
+func overlappingSlots(board []int) int {
+	seen := make(map[int]int)
+	for _, sh := range shapes {
+		for _, slots := range sh.placements {
+			if filled(board, slots) {
+				for _, k := range slots {
+					seen[k]++
+				}
+			}
+		}
+	}
+	n := 0
+	for _, c := range seen {
+		if c > 1 {
+			n++
+		}
+	}
+	return n
+}
+
+// distinctColors function returns the number of distinct colors currently on the board.
+func distinctColors(board []int) int {
+	seen := make(map[int]bool)
+	for _, v := range board {
+		if v > 0 {
+			seen[v] = true
+		}
+	}
+	return len(seen)
+}
+
+// drawOdds function returns the probability of drawing each color in play, indexed by color (0-based),
+// for the lucky color luckyColor (1-based), according to colorWeights.
+func drawOdds(luckyColor int) []float64 {
+	odds := colorWeights(luckyColor)
+	sum := 0.0
+	for _, w := range odds {
+		sum += w
+	}
+	for k := range odds {
+		odds[k] /= sum
+	}
+	return odds
+}
+
+// rarityScore function returns the rarity-weighted score of the toys in acq: the sum over the colors of the
+// acquired count divided by the draw probability of the color, normalized so that a collection following the draw
+// odds scores 1. Collecting more toys of the rare colors scores higher. Colors that cannot be drawn are ignored.
+func rarityScore(acq []int, odds []float64) float64 {
+	weighted, total, drawable := 0.0, 0, 0
+	for k, p := range odds {
+		if p <= 0 {
+			continue
+		}
+		weighted += float64(acq[k]) / p
+		total += acq[k]
+		drawable++
+	}
+	if total == 0 {
+		return 0
+	}
+	return weighted / float64(total*drawable)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestPeakDistinctColors(t *testing.T) {
+	useSeed(t, 1)
+	var s gameStats
+	for _, board := range [][]int{
+		{1, 2, 3, 0, 0, 0, 0, 0, 0},
+		{1, 2, 3, 4, 5, 1, 2, 0, 0},
+		{1, 1, 0, 0, 0, 0, 0, 0, 0},
+	} {
+		s.observePlacement(board)
+	}
+	if res := newGameResult(1, 9, make([]int, *numColors), s); res.PeakDistinctColors != 5 {
+		t.Errorf("peak distinct colors = %d, want 5", res.PeakDistinctColors)
+	}
+}
+
+func TestLongestDryStreak(t *testing.T) {
+	var s gameStats
+	pair := []ev{{map[int]int{1: 2}, eventOnePair}}
+	for _, events := range [][]ev{pair, nil, nil, nil, pair, nil, nil, pair, nil} {
+		s.observeTurn(events, 1)
+	}
+	if s.longestDryStreak != 3 {
+		t.Errorf("longest dry streak = %d, want 3", s.longestDryStreak)
+	}
+}
+
+func TestRarityScore(t *testing.T) {
+	// Red is drawn half of the time, Yellow and Purple a quarter each.
+	odds := []float64{0.5, 0.25, 0.25}
+	common := rarityScore([]int{4, 0, 0}, odds)
+	rare := rarityScore([]int{0, 4, 0}, odds)
+	if rare <= common {
+		t.Errorf("rarity of 4 Yellow toys = %v, want it above the %v of 4 Red toys", rare, common)
+	}
+}
+
+func TestGameEfficiency(t *testing.T) {
+	useSeed(t, demoSeed)
+	g := newGame(1, 30)
+	res := playGame(g, discardRenderer{})
+	events, placed := 0, 0
+	for _, rec := range g.stats.turns {
+		events += len(rec.events)
+		placed += rec.placed
+	}
+	if want := float64(events) / float64(placed); res.Efficiency != want {
+		t.Errorf("efficiency = %v, want %d events / %d placements = %v", res.Efficiency, events, placed, want)
+	}
+}
+
+func TestOverlappingSlots(t *testing.T) {
+	// The center slot lies in both the completed middle row and the completed middle column.
+	board := []int{2, 1, 3, 1, 1, 1, 4, 1, 5}
+	if n := overlappingSlots(board); n != 1 {
+		t.Errorf("overlapping slots = %d, want 1", n)
+	}
+	var s gameStats
+	s.observeOverlaps(overlappingSlots(board))
+	s.observeOverlaps(overlappingSlots([]int{1, 1, 1, 2, 3, 4, 5, 6, 7}))
+	if s.overlaps != 1 || s.maxOverlaps != 1 {
+		t.Errorf("overlaps = %d, at most %d in a turn, want 1 and 1", s.overlaps, s.maxOverlaps)
+	}
+}
+
+func TestClosestApproach(t *testing.T) {
+	var s gameStats
+	s.observePlacement([]int{1, 2, 3, 4, 5, 6, 7, 0, 0})
+	s.observeFreed(3)
+	s.observeTurn([]ev{{map[int]int{1: 3}, eventLuckyStrike}}, 7)
+	s.observePlacement([]int{1, 1, 2, 2, 3, 3, 4, 4, 5})
+	s.observeFreed(6)
+	s.observeTurn([]ev{{map[int]int{1: 2}, eventOnePair}, {map[int]int{2: 2}, eventOnePair}}, 2)
+	want := map[string]int{eventNames[eventAllDifferent]: 7, eventNames[eventClear]: 6}
+	if got := s.closestApproach(); !maps.Equal(got, want) {
+		t.Errorf("closest approach of a game that never clears = %v, want %v", got, want)
+	}
+	s.observeTurn([]ev{{map[int]int{1: 1, 2: 1}, eventAllDifferent}}, 0)
+	want = map[string]int{eventNames[eventClear]: 6}
+	if got := s.closestApproach(); !maps.Equal(got, want) {
+		t.Errorf("closest approach after a Family Portrait = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// stickySpec lists the sticky slots of the board, comma-separated, e.g. "4" for the center slot. The toy of a sticky
+// slot sticks: when it takes part in a line, a shape or a pair, the match is awarded as usual, its toy included, but
+// the slot is not cleared and is not returned to the empty slots. The toy thus stays available to the next matches of
+// its color, and is acquired again each time, then once more with the leftovers at the end if it is still there.
+// A match whose toys are all in sticky slots clears them, as it would otherwise fire again on every turn. A filled
+// sticky slot keeps Clear The Board from firing, and the reset of a Family Portrait empties it. Sticky slots only
+// apply to the main board, not to the bonus board of -bonus-board, and cannot be combined with -gravity, which moves
+// the toys between the slots.
+var stickySpec = flag.String("sticky", "", "comma-separated slots whose toy is not cleared by the matches it takes part in")
+
+// stickySlots holds the sticky slots of -sticky.
+var stickySlots = map[int]bool{}
+
+// parseStickySlots function parses the slots of -sticky into stickySlots.
+func parseStickySlots(spec string) error {
+	for _, field := range strings.Split(spec, ",") {
+		k, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || k < 0 || k >= len(initialOrderedSlots) {
+			return fmt.Errorf("invalid slot %q, want 0 to %d", field, len(initialOrderedSlots)-1)
+		}
+		stickySlots[k] = true
+	}
+	return nil
+}
+
+// allSticky function reports whether every slot of slots is sticky.
+func allSticky(slots []int) bool {
+	for _, k := range slots {
+		if !stickySlots[k] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStickySlotTriple(t *testing.T) {
+	saveEvents(t)
+	old := stickySlots
+	stickySlots = map[int]bool{}
+	t.Cleanup(func() { stickySlots = old })
+	if err := parseStickySlots("4"); err != nil {
+		t.Fatal(err)
+	}
+	board := []int{2, 3, 5, 1, 1, 1, 6, 7, 8}
+	events, empty, _ := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if !slices.Contains(eventTypes(events), eventLuckyStrike) {
+		t.Fatalf("events = %v, want the middle row to fire %s", eventTypes(events), eventDesc[eventLuckyStrike])
+	}
+	if want := []int{2, 3, 5, 0, 1, 0, 6, 7, 8}; !slices.Equal(board, want) {
+		t.Errorf("board = %v, want the sticky center to keep its Red toy, %v", board, want)
+	}
+	if slices.Contains(empty, 4) {
+		t.Errorf("empty slots = %v, want the sticky center left out", empty)
+	}
+}
+
+func TestParseStickySlotsInvalid(t *testing.T) {
+	old := stickySlots
+	stickySlots = map[int]bool{}
+	t.Cleanup(func() { stickySlots = old })
+	for _, spec := range []string{"x", "-1", "9"} {
+		if err := parseStickySlots(spec); err == nil {
+			t.Errorf("-sticky %q was accepted", spec)
+		}
+	}
+}
+
+func TestStickyPairFullBoard(t *testing.T) {
+	saveEvents(t)
+	old := stickySlots
+	stickySlots = map[int]bool{0: true, 1: true}
+	t.Cleanup(func() { stickySlots = old })
+	// The two sticky Red toys pair on a full board: with no other toy in the pair they are cleared, and Family
+	// Portrait does not fire, as the colors of the board are not all distinct.
+	board := []int{1, 1, 2, 3, 4, 5, 6, 7, 8}
+	events, empty, reset := checkBoard(board, emptySlots(board), make([]ev, 0))
+	if want := []int{eventOnePair}; !slices.Equal(eventTypes(events), want) || reset {
+		t.Errorf("events = %v, reset %v, want %v and no reset", eventTypes(events), reset, want)
+	}
+	if want := []int{0, 1}; !slices.Equal(empty, want) {
+		t.Errorf("empty slots = %v, want the sticky pair cleared, %v", empty, want)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// targetsSpec sets how many toys of each color the player aims to collect: a single count for every color in play,
+// or one count per color in palette order. The progress toward the targets is shown with the acquired toys,
+// e.g. "Red: 2/3", and with stopAtTargets the game ends as soon as every target is met.
+var (
+	targetsSpec   = flag.String("targets", "", "toys to collect of each color: a count for every color, or one count per color")
+	stopAtTargets = flag.Bool("stop-at-targets", false, "end the game as soon as every target of -targets is met")
+)
+
+// targets holds the target of each color in play, indexed by color (0-based), or nil without -targets.
+var targets []int
+
+// initTargets function parses the targets of -targets.
+func initTargets() error {
+	t, err := parseColorCounts(*targetsSpec)
+	targets = t
+	return err
+}
+
+// targetsMet function reports whether acq, indexed by color (0-based), holds at least the target of every color.
+// It is false without -targets.
+func targetsMet(acq []int) bool {
+	if targets == nil {
+		return false
+	}
+	for k, n := range targets {
+		if acq[k] < n {
+			return false
+		}
+	}
+	return true
+}
+
+// acquiredLabel function returns the count n of toys acquired for the color k (0-based), against its target if any.
+func acquiredLabel(n, k int) string {
+	if targets == nil {
+		return strconv.Itoa(n)
+	}
+	return fmt.Sprintf("%d/%d", n, targets[k])
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestStopAtTargets(t *testing.T) {
+	useSeed(t, 6)
+	setFlag(t, "stop-at-targets", "true")
+	setFlag(t, "targets", "2,2,2,0,0,0,0,0,0")
+	old := targets
+	t.Cleanup(func() { targets = old })
+	if err := initTargets(); err != nil {
+		t.Fatal(err)
+	}
+	g := newGame(1, 30)
+	for !g.over() {
+		if targetsMet(g.acquired) {
+			t.Fatalf("turn %d: the targets are met but the game goes on", g.turn)
+		}
+		g.playTurn(discardRenderer{})
+	}
+	if !targetsMet(g.acquired) {
+		t.Fatalf("the game ended on turn %d with %v, before the targets were met", g.turn, g.acquired[:3])
+	}
+	if res := g.finish(); res.TerminationReason != endTargetsReached {
+		t.Errorf("the game ended with %q, want %q", res.TerminationReason, endTargetsReached)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"strconv"
+)
+
+// timelineJSON is a JSON file receiving the events of the game indexed by turn, for charting libraries.
+var timelineJSON = flag.String("timeline-json", "", "write the events and the cumulative toy counts of each turn to this JSON file")
+
+// timeline is the JSON object written to -timeline-json. Turns maps each turn number to what happened during the turn;
+// Acquired and Total are the final outcome, which also includes the toys left on the board at the end of the game.
+type timeline struct {
+	Turns    map[string]timelineTurn `json:"turns"`
+	Acquired map[string]int          `json:"acquired"`
+	Total    int                     `json:"total"`
+}
+
+// timelineTurn holds the events of a turn and the cumulative toy counts right after it.
+type timelineTurn struct {
+	Events   []string       `json:"events"`
+	Acquired map[string]int `json:"acquired"`
+	Total    int            `json:"total"`
+}
+
+// newTimeline function builds the timeline of a game from its turn records and its final outcome.
+func newTimeline(records []turnRecord, res gameResult) timeline {
+	t := timeline{Turns: make(map[string]timelineTurn, len(records)), Acquired: res.Acquired, Total: res.Total}
+	for _, rec := range records {
+		t.Turns[strconv.Itoa(rec.turn)] = timelineTurn{
+			Events:   rec.events,
+			Acquired: colorTally(rec.acquired),
+			Total:    total(rec.acquired),
+		}
+	}
+	return t
+}
+
+// writeTimeline function writes the timeline of a game to path as JSON.
+func writeTimeline(path string, records []turnRecord, res gameResult) error {
+	data, err := json.MarshalIndent(newTimeline(records, res), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
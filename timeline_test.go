@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestTimelineMatchesAcquired(t *testing.T) {
+	useSeed(t, 4)
+	g := newGame(2, 30)
+	res := playGame(g, discardRenderer{})
+	path := filepath.Join(t.TempDir(), "timeline.json")
+	if err := writeTimeline(path, g.stats.turns, res); err != nil {
+		t.Fatalf("write the timeline: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tl timeline
+	if err := json.Unmarshal(data, &tl); err != nil {
+		t.Fatalf("decode the timeline: %v", err)
+	}
+	if len(tl.Turns) != g.turn {
+		t.Fatalf("the timeline holds %d turns, want %d", len(tl.Turns), g.turn)
+	}
+	// The final outcome adds the toys left on the board to the counts after the last turn.
+	last := tl.Turns[strconv.Itoa(g.turn)]
+	leftovers := make(map[string]int)
+	for _, v := range g.board {
+		if v > 0 {
+			leftovers[colors[v-1]]++
+		}
+	}
+	for c, n := range res.Acquired {
+		if got := last.Acquired[c] + leftovers[c]; got != n {
+			t.Errorf("%s: %d toys after the last turn and %d left on the board, want %d acquired",
+				c, last.Acquired[c], leftovers[c], n)
+		}
+	}
+	if tl.Total != res.Total || last.Total+len(g.board)-len(emptySlots(g.board)) != res.Total {
+		t.Errorf("timeline totals %d and %d after the last turn, want %d", tl.Total, last.Total, res.Total)
+	}
+}
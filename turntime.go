@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// timeTurns records how long the player takes at the prompt after each turn, measured with now, and reports the mean
+// and median think time once the game is over. The demo and the scripted games have no player to time.
+var timeTurns = flag.Bool("time-turns", false, "report the mean and median time spent on each turn")
+
+// timed function calls prompt and returns its answer, with the time the player took to give it, measured with now.
+func timed(prompt func() bool) (bool, time.Duration) {
+	start := now()
+	answer := prompt()
+	return answer, now().Sub(start)
+}
+
+// printThinkTimes function prints the mean and median of the think times of the turns of a game.
+func printThinkTimes(w io.Writer, times []time.Duration) {
+	if len(times) == 0 {
+		return
+	}
+	sorted := slices.Clone(times)
+	slices.Sort(sorted)
+	var sum time.Duration
+	for _, t := range sorted {
+		sum += t
+	}
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + median) / 2
+	}
+	_, _ = fmt.Fprintf(w, "Think time: mean %s, median %s over %d turns\n",
+		(sum / time.Duration(len(sorted))).Round(time.Millisecond), median.Round(time.Millisecond), len(sorted))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestThinkTimes(t *testing.T) {
+	clock := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	useClock(t, func() time.Time { return clock })
+	// The player takes 2s, 4s, 1s and then 9s to answer the prompt.
+	thinks := []time.Duration{2 * time.Second, 4 * time.Second, time.Second, 9 * time.Second}
+	recorded := make([]time.Duration, 0)
+	for _, d := range thinks {
+		proceed, think := timed(func() bool {
+			clock = clock.Add(d)
+			return true
+		})
+		if !proceed {
+			t.Fatal("timed dropped the answer of the prompt")
+		}
+		recorded = append(recorded, think)
+	}
+	if !slices.Equal(recorded, thinks) {
+		t.Errorf("recorded think times %v, want %v", recorded, thinks)
+	}
+	var buf bytes.Buffer
+	printThinkTimes(&buf, recorded)
+	if got, want := buf.String(), "Think time: mean 4s, median 3s over 4 turns\n"; got != want {
+		t.Errorf("think time report = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// webhookURL is the endpoint notified with a JSON POST whenever a rare event occurs.
+var webhookURL = flag.String("webhook", "", "URL to POST a JSON notification to on rare events")
+
+// webhookTimeout bounds each notification so that a slow endpoint can never hold up the end of the game.
+const webhookTimeout = 3 * time.Second
+
+// rareEvents is the set of events that trigger a webhook notification.
+var rareEvents = map[int]bool{
+	eventAllDifferent: true,
+	eventClear:        true,
+}
+
+// webhookPayload is the body of a webhook notification.
+// Board lists the color of each slot, with an empty string for empty slots, and Acquired holds the toys collected so far.
+type webhookPayload struct {
+	Event     string         `json:"event"`
+	Reward    int            `json:"reward"`
+	Board     []string       `json:"board"`
+	Acquired  map[string]int `json:"acquired"`
+	Remaining int            `json:"remaining"`
+}
+
+var (
+	webhookClient = &http.Client{Timeout: webhookTimeout}
+	webhookWG     sync.WaitGroup
+)
+
+// notifyRareEvents function posts a notification to the configured webhook for every rare event in events.
+// Notifications are fire-and-forget: each one is sent from its own goroutine and failures are only logged.
+func notifyRareEvents(events []ev, board, acq []int, remaining int) {
+	if *webhookURL == "" {
+		return
+	}
+	for _, e := range events {
+		if !rareEvents[e.event] {
+			continue
+		}
+		payload := webhookPayload{
+			Event:     eventDesc[e.event],
+			Reward:    eventRewardRules[e.event],
+			Board:     boardLabels(board),
+			Acquired:  colorTally(acq),
+			Remaining: remaining,
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			slog.Warn("webhook: encode notification failed", "event", payload.Event, "err", err)
+			continue
+		}
+		webhookWG.Add(1)
+		go func() {
+			defer webhookWG.Done()
+			resp, err := webhookClient.Post(*webhookURL, "application/json", bytes.NewReader(body))
+			if err != nil {
+				slog.Warn("webhook: post notification failed", "event", payload.Event, "err", err)
+				return
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode >= http.StatusBadRequest {
+				slog.Warn("webhook: post notification failed", "event", payload.Event, "status", resp.Status)
+			}
+		}()
+	}
+}
+
+// waitWebhooks function waits for the notifications still in flight, each of which is bounded by webhookTimeout.
+// It runs once the games are over, whether they were played alone or side by side under -split.
+func waitWebhooks() {
+	webhookWG.Wait()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestNotifyRareEventsClear(t *testing.T) {
+	payloads := make(chan webhookPayload, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode the notification: %v", err)
+		}
+		payloads <- p
+	}))
+	defer srv.Close()
+	setFlag(t, "webhook", srv.URL)
+
+	board := make([]int, len(initialOrderedSlots))
+	acq := []int{2, 0, 3, 0, 0, 0, 0, 0, 0}
+	notifyRareEvents([]ev{{map[int]int{1: 2}, eventOnePair}, {map[int]int{}, eventClear}}, board, acq, 7)
+	waitWebhooks()
+	close(payloads)
+
+	p, ok := <-payloads
+	if !ok {
+		t.Fatal("no notification was posted")
+	}
+	if p.Event != "Clear The Board" || p.Reward != eventRewardRules[eventClear] || p.Remaining != 7 {
+		t.Errorf("notification = %+v, want Clear The Board, reward %d and 7 remaining", p, eventRewardRules[eventClear])
+	}
+	if !slices.Equal(p.Board, boardLabels(board)) || p.Acquired["Red"] != 2 || p.Acquired["Purple"] != 3 {
+		t.Errorf("notification state = %v and %v, want the board and the toys acquired", p.Board, p.Acquired)
+	}
+	if _, more := <-payloads; more {
+		t.Error("One Pair was notified, want only the rare events")
+	}
+}
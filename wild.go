@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"slices"
+)
+
+// wildChance is the chance, per toy placed, of a wild draw: a toy of a color drawn like the others is placed in a
+// random empty slot, instead of the next slot in order, on top of the normal placement. The wild toy counts as placed
+// from the package like the others, and fires the Wild Draw event, which awards no points. A wild draw needs another
+// empty slot and a toy left to place, and none occurs on a full board. See wildDraw.
+var wildChance = flag.Float64("wild-chance", 0, "chance per toy placed of a wild draw, an extra toy in a random empty slot (0 to 1)")
+
+// eventWildDraw is the event type of Wild Draw, registered by registerWildDraw under -wild-chance.
+var eventWildDraw = -1
+
+// registerWildDraw function registers the Wild Draw event next to the built-in events.
+func registerWildDraw() {
+	eventWildDraw = registerEvent("Wild Draw", "an extra toy was placed in a random empty slot, %d more toys to place", 0)
+}
+
+// wildDraw function places a wild toy, drawn with draw, in a random slot of orderedEmptySlots with the chance of
+// -wild-chance, and returns the events, the empty slots left and the toys that remain to be placed out of remaining.
+// The chance and the slot are rolled with roll, which is only used under -wild-chance, so that the games of a seed are
+// unchanged without it.
+func wildDraw(board, orderedEmptySlots []int, events []ev, remaining int, draw func() int, roll func() float64) ([]ev, []int, int) {
+	if *wildChance <= 0 || len(orderedEmptySlots) == 0 || remaining <= 0 || roll() >= *wildChance {
+		return events, orderedEmptySlots, remaining
+	}
+	color := draw()
+	if color == 0 {
+		return events, orderedEmptySlots, remaining
+	}
+	i := int(roll() * float64(len(orderedEmptySlots)))
+	board[orderedEmptySlots[i]] = color
+	events = append(events, ev{map[int]int{color: 0}, eventWildDraw})
+	return events, slices.Delete(slices.Clone(orderedEmptySlots), i, i+1), remaining - 1
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWildDrawChance(t *testing.T) {
+	saveEvents(t)
+	registerWildDraw()
+	for _, chance := range []string{"0", "1"} {
+		setFlag(t, "wild-chance", chance)
+		useSeed(t, 2)
+		g := newGame(1, 30)
+		for !g.over() {
+			if g.turn > 1000 {
+				t.Fatalf("-wild-chance=%s: the game is still going after %d turns", chance, g.turn)
+			}
+			// A wild draw needs an empty slot and a toy to place besides those of the normal placement.
+			room := len(g.orderedEmptySlots) >= 2 && g.remaining >= 2
+			events := g.playTurn(discardRenderer{})
+			wild := slices.ContainsFunc(events, func(e ev) bool { return e.event == eventWildDraw })
+			if want := chance == "1" && room; wild != want {
+				t.Errorf("-wild-chance=%s, turn %d: wild draw %v, want %v", chance, g.turn, wild, want)
+			}
+		}
+	}
+}